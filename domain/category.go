@@ -0,0 +1,291 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// How a stat is combined when stat lines are added together
+type AggregateRule int
+
+const (
+	// Counting stats such as yards are added up
+	AggregateSum AggregateRule = iota
+	// Longest plays take the highest value
+	AggregateMax
+	// Averages are recalculated from the added up numerator and denominator fields
+	AggregateRatio
+)
+
+// A stat category such as passing. Declaring a category here is all that's needed for it to be
+// read from the feed, saved, added up and returned by the API
+type StatCategory struct {
+	// The key of the category's section in the feed, also used in stat keys such as "passing.yds"
+	FeedKey string
+	// The key of the category in json responses
+	JsonKey string
+	// The table the category's stats are stored in, saved through the Save{Table} procedure
+	Table string
+	// The label of the field minimum attempts are checked against on leaderboards
+	AttemptsLabel string
+	Fields []StatField
+}
+
+// A stat in a category. The label is the stat's key in the feed, in json and its storage column
+type StatField struct {
+	Label string
+	Aggregate AggregateRule
+	// For ratio fields, the labels of the fields the ratio is calculated from
+	Numerator string
+	Denominator string
+	// Feed labels the value is calculated from, when it isn't in the feed directly
+	FeedLabels []string
+	FromFeed func(feedValues []float64) float64
+}
+
+// Every stat category, in the order they're shown
+var statCategories = []StatCategory {
+	{
+		FeedKey: "passing",
+		JsonKey: "passingStats",
+		Table: "PassingStats",
+		AttemptsLabel: "att",
+		Fields: []StatField {
+			{Label: "att"},
+			{Label: "cmp"},
+			{Label: "yds"},
+			{Label: "tds"},
+			{Label: "ints"},
+			{Label: "twopta"},
+			{Label: "twoptm"},
+		},
+	},
+	{
+		FeedKey: "rushing",
+		JsonKey: "rushingStats",
+		Table: "RushingStats",
+		AttemptsLabel: "att",
+		Fields: []StatField {
+			{Label: "att"},
+			{Label: "yds"},
+			{Label: "tds"},
+			{Label: "lng", Aggregate: AggregateMax},
+			{Label: "lngtd", Aggregate: AggregateMax},
+			{Label: "twopta"},
+			{Label: "twoptm"},
+		},
+	},
+	{
+		FeedKey: "receiving",
+		JsonKey: "receivingStats",
+		Table: "ReceivingStats",
+		AttemptsLabel: "rec",
+		Fields: []StatField {
+			{Label: "rec"},
+			{Label: "yds"},
+			{Label: "tds"},
+			{Label: "lng", Aggregate: AggregateMax},
+			{Label: "lngtd", Aggregate: AggregateMax},
+			{Label: "twopta"},
+			{Label: "twoptm"},
+		},
+	},
+	{
+		FeedKey: "kicking",
+		JsonKey: "kickingStats",
+		Table: "KickingStats",
+		AttemptsLabel: "fga",
+		Fields: []StatField {
+			{Label: "fgm"},
+			{Label: "fga"},
+			{Label: "fgyds", Aggregate: AggregateMax},
+			{Label: "xpmade"},
+			{Label: "xpa"},
+			{Label: "totpfg"},
+		},
+	},
+	{
+		FeedKey: "defense",
+		JsonKey: "defenseStats",
+		Table: "DefenseStats",
+		Fields: []StatField {
+			{Label: "tkl"},
+			{Label: "ast"},
+			{Label: "sk"},
+			{Label: "int"},
+			{Label: "ffum"},
+		},
+	},
+	{
+		FeedKey: "fumbles",
+		JsonKey: "fumbleStats",
+		Table: "FumbleStats",
+		Fields: []StatField {
+			{Label: "tot"},
+			{Label: "rcv"},
+			{Label: "trcv"},
+			{Label: "yds"},
+			{Label: "lost"},
+		},
+	},
+	newReturnStatCategory("kickret", "kickReturnStats", "KickReturnStats"),
+	newReturnStatCategory("puntret", "puntReturnStats", "PuntReturnStats"),
+	{
+		FeedKey: "punting",
+		JsonKey: "puntingStats",
+		Table: "PuntingStats",
+		AttemptsLabel: "pts",
+		Fields: []StatField {
+			{Label: "pts"},
+			{Label: "yds"},
+			{Label: "avg", Aggregate: AggregateRatio, Numerator: "yds", Denominator: "pts"},
+			{Label: "i20"},
+			{Label: "lng", Aggregate: AggregateMax},
+		},
+	},
+}
+
+// Kick and punt returns share their fields. The feed has no return yards, only the number of returns and the
+// average rounded to a whole yard, so yds is an estimate from the two and can be a few yards off the real total
+func newReturnStatCategory(feedKey string, jsonKey string, table string) StatCategory {
+	return StatCategory {
+		FeedKey: feedKey,
+		JsonKey: jsonKey,
+		Table: table,
+		AttemptsLabel: "ret",
+		Fields: []StatField {
+			{Label: "ret"},
+			{
+				Label: "yds",
+				FeedLabels: []string{"ret", "avg"},
+				// An estimate: returns times the feed's rounded average
+				FromFeed: func(feedValues []float64) float64 {
+					return math.Round(feedValues[0] * feedValues[1])
+				},
+			},
+			{Label: "tds"},
+			{Label: "lng", Aggregate: AggregateMax},
+			{Label: "lngtd", Aggregate: AggregateMax},
+		},
+	}
+}
+
+// Get every stat category
+func GetStatCategories() []StatCategory {
+	return statCategories
+}
+
+// Get a stat category by its feed key
+func GetStatCategory(feedKey string) (StatCategory, bool) {
+	for _, category := range statCategories {
+		if category.FeedKey == strings.ToLower(feedKey) {
+			return category, true
+		}
+	}
+	return StatCategory{}, false
+}
+
+// Get the labels the category's stats are read from in the feed
+func (category StatCategory) GetFeedLabels() []string {
+	var labels []string
+	isAdded := make(map[string]bool)
+
+	for _, field := range category.Fields {
+		feedLabels := field.FeedLabels
+		if field.FromFeed == nil {
+			feedLabels = []string{field.Label}
+		}
+
+		for _, label := range feedLabels {
+			if !isAdded[label] {
+				isAdded[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// Get a field in the category by its label
+func (category StatCategory) GetField(label string) (StatField, bool) {
+	for _, field := range category.Fields {
+		if field.Label == strings.ToLower(label) {
+			return field, true
+		}
+	}
+	return StatField{}, false
+}
+
+// Read the category's stats for a player from their entry in the feed.
+// Fields that are missing or can't be read are left out, so they're unknown rather than 0
+func (category StatCategory) NewCategoryStats(feedStats map[string]interface{}) CategoryStats {
+	stats := make(CategoryStats)
+
+	for _, field := range category.Fields {
+		if field.FromFeed == nil {
+			value, ok := feedStats[field.Label].(float64)
+			if ok {
+				stats[field.Label] = value
+			}
+			continue
+		}
+
+		var feedValues []float64
+		for _, label := range field.FeedLabels {
+			value, ok := feedStats[label].(float64)
+			if !ok {
+				break
+			}
+			feedValues = append(feedValues, value)
+		}
+
+		if len(feedValues) == len(field.FeedLabels) {
+			stats[field.Label] = field.FromFeed(feedValues)
+		}
+	}
+
+	return stats
+}
+
+// Add two sets of the category's stats together using each field's aggregate rule.
+// A field known in only one set keeps that value, and stays unknown if it's in neither
+func (category StatCategory) add(stats CategoryStats, other CategoryStats) CategoryStats {
+	total := make(CategoryStats)
+
+	for _, field := range category.Fields {
+		value, ok := stats[field.Label]
+		otherValue, otherOk := other[field.Label]
+
+		if !ok || !otherOk {
+			if ok {
+				total[field.Label] = value
+			} else if otherOk {
+				total[field.Label] = otherValue
+			}
+			continue
+		}
+
+		switch field.Aggregate {
+		case AggregateSum:
+			total[field.Label] = value + otherValue
+		case AggregateMax:
+			total[field.Label] = math.Max(value, otherValue)
+		}
+	}
+
+	// Ratios need the added up fields, so they're calculated last
+	for _, field := range category.Fields {
+		if field.Aggregate != AggregateRatio {
+			continue
+		}
+
+		numerator, okNumerator := total[field.Numerator]
+		denominator, okDenominator := total[field.Denominator]
+		delete(total, field.Label)
+		if okNumerator && okDenominator {
+			total[field.Label] = getRate(numerator, denominator)
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCategoryStatsMissingFields(t *testing.T) {
+	rushing, _ := GetStatCategory("rushing")
+	kickReturns, _ := GetStatCategory("kickret")
+
+	tests := []struct {
+		name string
+		category StatCategory
+		feedStats map[string]interface{}
+		expected CategoryStats
+	}{
+		{"every field", rushing, map[string]interface{}{"att": 12.0, "yds": 54.0, "tds": 1.0, "lng": 17.0,
+			"lngtd": 4.0, "twopta": 0.0, "twoptm": 0.0},
+			CategoryStats{"att": 12, "yds": 54, "tds": 1, "lng": 17, "lngtd": 4, "twopta": 0, "twoptm": 0}},
+		{"missing fields are left out", rushing, map[string]interface{}{"att": 12.0, "yds": 54.0},
+			CategoryStats{"att": 12, "yds": 54}},
+		{"unreadable fields are left out", rushing, map[string]interface{}{"att": 12.0, "yds": "54"},
+			CategoryStats{"att": 12}},
+		{"nothing in the feed", rushing, map[string]interface{}{}, CategoryStats{}},
+		// yds comes from ret and avg, so it's unknown without both
+		{"a field from a missing feed label", kickReturns, map[string]interface{}{"ret": 3.0, "tds": 0.0},
+			CategoryStats{"ret": 3, "tds": 0}},
+	}
+
+	for _, test := range tests {
+		if stats := test.category.NewCategoryStats(test.feedStats); !reflect.DeepEqual(stats, test.expected) {
+			t.Errorf("%v: got %v, expected %v", test.name, stats, test.expected)
+		}
+	}
+}
+
+func TestAddMissingFields(t *testing.T) {
+	tests := []struct {
+		name string
+		line StatLine
+		other StatLine
+		expected CategoryStats
+	}{
+		{"known in both", StatLine{"punting": {"pts": 4, "yds": 180}}, StatLine{"punting": {"pts": 6, "yds": 270}},
+			CategoryStats{"pts": 10, "yds": 450, "avg": 45}},
+		{"known in one keeps its value", StatLine{"punting": {"pts": 4, "yds": 180, "lng": 52}},
+			StatLine{"punting": {"pts": 6}}, CategoryStats{"pts": 10, "yds": 180, "avg": 18, "lng": 52}},
+		{"known in neither stays unknown", StatLine{"punting": {"pts": 4}}, StatLine{"punting": {"pts": 6}},
+			CategoryStats{"pts": 10}},
+		{"a category in one line only", StatLine{"punting": {"pts": 4, "yds": 180}}, StatLine{},
+			CategoryStats{"pts": 4, "yds": 180, "avg": 45}},
+	}
+
+	for _, test := range tests {
+		if total := test.line.Add(test.other)["punting"]; !reflect.DeepEqual(total, test.expected) {
+			t.Errorf("%v: got %v, expected %v", test.name, total, test.expected)
+		}
+	}
+}
+
+func TestAggregateRules(t *testing.T) {
+	category := StatCategory{
+		FeedKey: "test",
+		Fields: []StatField{
+			{Label: "att"},
+			{Label: "yds"},
+			{Label: "lng", Aggregate: AggregateMax},
+			{Label: "avg", Aggregate: AggregateRatio, Numerator: "yds", Denominator: "att"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		stats CategoryStats
+		other CategoryStats
+		expected CategoryStats
+	}{
+		{"sums add up, max keeps the highest and ratios are recalculated",
+			CategoryStats{"att": 10, "yds": 45, "lng": 12, "avg": 4.5},
+			CategoryStats{"att": 5, "yds": 60, "lng": 31, "avg": 12},
+			CategoryStats{"att": 15, "yds": 105, "lng": 31, "avg": 7}},
+		{"max keeps the first when it's higher", CategoryStats{"att": 1, "yds": 40, "lng": 40},
+			CategoryStats{"att": 1, "yds": 5, "lng": 5}, CategoryStats{"att": 2, "yds": 45, "lng": 40, "avg": 22.5}},
+		{"ratio without a denominator", CategoryStats{"att": 0, "yds": 0}, CategoryStats{"att": 0, "yds": 0},
+			CategoryStats{"att": 0, "yds": 0, "avg": 0}},
+		{"adding to nothing", CategoryStats{}, CategoryStats{"att": 3, "yds": 10, "lng": 6},
+			CategoryStats{"att": 3, "yds": 10, "lng": 6, "avg": 3.3}},
+	}
+
+	for _, test := range tests {
+		if total := category.add(test.stats, test.other); !reflect.DeepEqual(total, test.expected) {
+			t.Errorf("%v: got %v, expected %v", test.name, total, test.expected)
+		}
+	}
+}
+
+func TestGetFeedLabels(t *testing.T) {
+	tests := []struct {
+		feedKey string
+		expected []string
+	}{
+		{"passing", []string{"att", "cmp", "yds", "tds", "ints", "twopta", "twoptm"}},
+		// Return yards are calculated from ret and avg, which are only read once
+		{"kickret", []string{"ret", "avg", "tds", "lng", "lngtd"}},
+		{"puntret", []string{"ret", "avg", "tds", "lng", "lngtd"}},
+		{"punting", []string{"pts", "yds", "avg", "i20", "lng"}},
+	}
+
+	for _, test := range tests {
+		category, ok := GetStatCategory(test.feedKey)
+		if !ok {
+			t.Fatalf("%v: no category", test.feedKey)
+		}
+		if labels := category.GetFeedLabels(); !reflect.DeepEqual(labels, test.expected) {
+			t.Errorf("%v: got %v, expected %v", test.feedKey, labels, test.expected)
+		}
+	}
+}
+
+func TestNewCategoryStatsReturnYards(t *testing.T) {
+	tests := []struct {
+		name string
+		feedStats map[string]interface{}
+		expected float64
+	}{
+		{"returns times the average", map[string]interface{}{"ret": 3.0, "avg": 23.0}, 69},
+		{"rounded to a whole yard", map[string]interface{}{"ret": 3.0, "avg": 7.5}, 23},
+		{"no returns", map[string]interface{}{"ret": 0.0, "avg": 0.0}, 0},
+	}
+
+	for _, feedKey := range []string{"kickret", "puntret"} {
+		category, _ := GetStatCategory(feedKey)
+		for _, test := range tests {
+			stats := category.NewCategoryStats(test.feedStats)
+			if yards, ok := stats["yds"]; !ok || yards != test.expected {
+				t.Errorf("%v %v: got %v, expected %v", feedKey, test.name, stats["yds"], test.expected)
+			}
+			if _, ok := stats["avg"]; ok {
+				t.Errorf("%v %v: got avg %v, expected it to only be used for the yards", feedKey, test.name,
+					stats["avg"])
+			}
+		}
+	}
+}
+
+// Every category must be declared so it can be saved and added up
+func TestStatCategoriesAreValid(t *testing.T) {
+	for _, category := range GetStatCategories() {
+		if len(category.FeedKey) == 0 || len(category.JsonKey) == 0 || len(category.Table) == 0 {
+			t.Errorf("%v: missing its feed key, json key or table", category.FeedKey)
+		}
+		if len(category.AttemptsLabel) > 0 {
+			if _, ok := category.GetField(category.AttemptsLabel); !ok {
+				t.Errorf("%v: attempts label %v isn't a field", category.FeedKey, category.AttemptsLabel)
+			}
+		}
+
+		for _, field := range category.Fields {
+			if field.Aggregate == AggregateRatio {
+				_, okNumerator := category.GetField(field.Numerator)
+				_, okDenominator := category.GetField(field.Denominator)
+				if !okNumerator || !okDenominator {
+					t.Errorf("%v.%v: ratio of unknown fields %v and %v", category.FeedKey, field.Label,
+						field.Numerator, field.Denominator)
+				}
+			}
+			if (field.FromFeed == nil) != (len(field.FeedLabels) == 0) {
+				t.Errorf("%v.%v: feed labels need a FromFeed function and the other way round", category.FeedKey,
+					field.Label)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import "sort"
+
+// Several players' stats side by side, with their weekly games lined up by week
+type PlayerComparison struct {
+	Weeks []ComparisonWeek `json:"weeks"`
+	Players []ComparedPlayer `json:"players"`
+}
+
+// A week of a season that games are lined up by. Preseason weeks count back from week 1, so -1 is
+// the week before the season starts
+type ComparisonWeek struct {
+	Season int `json:"season"`
+	Week int `json:"week"`
+}
+
+// One player's totals, per game averages and weekly games in a comparison
+type ComparedPlayer struct {
+	PlayerStatsSummary
+	// Per game averages keyed by stat key, with "fantasy" for fantasy points when scored
+	Averages map[string]float64 `json:"averages"`
+	// The player's game for each week in the comparison's weeks, or null if they didn't play
+	Weekly []*PlayerStats `json:"weekly"`
+}
+
+// Write the player's summary with their averages and weekly games added to it
+func (player ComparedPlayer) MarshalJSON() ([]byte, error) {
+	return marshalWithFields(player.PlayerStatsSummary, map[string]interface{} {
+		"averages": player.Averages,
+		"weekly": player.Weekly,
+	})
+}
+
+// Compare the games of the given players. Fantasy points are added when a ruleset is given
+func NewPlayerComparison(playerIds []int, playerStats []PlayerStats, ruleset *ScoringRuleset) PlayerComparison {
+	gamesByPlayer := groupPlayerStatsByPlayer(playerStats)
+	weeks := getWeeks(playerStats)
+
+	comparison := PlayerComparison {
+		Weeks: weeks,
+		Players: []ComparedPlayer{},
+	}
+
+	for _, playerId := range playerIds {
+		games, ok := gamesByPlayer[playerId]
+		if !ok {
+			continue
+		}
+		comparison.Players = append(comparison.Players, newComparedPlayer(games, weeks, ruleset))
+	}
+
+	return comparison
+}
+
+func newComparedPlayer(games []PlayerStats, weeks []ComparisonWeek, ruleset *ScoringRuleset) ComparedPlayer {
+	summary := NewPlayerStatsSummary(games)
+	metrics := NewMetrics(summary.StatLine)
+	summary.Metrics = &metrics
+
+	averages := make(map[string]float64)
+	for _, statKey := range GetStatKeys() {
+		value, _ := summary.StatLine.GetStat(statKey)
+		averages[statKey] = roundToTenth(value / float64(summary.Games))
+	}
+
+	if ruleset != nil {
+		points := ruleset.ScoreGames(games)
+		summary.FantasyPoints = &points
+		averages["fantasy"] = roundToTenth(points / float64(summary.Games))
+	}
+
+	// Line the games up with the weeks, leaving a gap for bye weeks and missed games
+	weekIndexes := make(map[ComparisonWeek]int)
+	for index, week := range weeks {
+		weekIndexes[week] = index
+	}
+	weekly := make([]*PlayerStats, len(weeks))
+	for i := range games {
+		gameStats := games[i]
+		gameMetrics := NewMetrics(gameStats.StatLine)
+		gameStats.Metrics = &gameMetrics
+
+		if ruleset != nil {
+			points := ruleset.Score(gameStats.StatLine)
+			gameStats.FantasyPoints = &points
+		}
+
+		weekly[weekIndexes[getComparisonWeek(gameStats)]] = &gameStats
+	}
+
+	return ComparedPlayer {
+		PlayerStatsSummary: summary,
+		Averages: averages,
+		Weekly: weekly,
+	}
+}
+
+// Get every week any of the games were played in, in order
+func getWeeks(playerStats []PlayerStats) []ComparisonWeek {
+	isWeekPlayed := make(map[ComparisonWeek]bool)
+	weeks := []ComparisonWeek{}
+
+	for _, gameStats := range playerStats {
+		week := getComparisonWeek(gameStats)
+		if !isWeekPlayed[week] {
+			isWeekPlayed[week] = true
+			weeks = append(weeks, week)
+		}
+	}
+
+	sort.Slice(weeks, func(i, j int) bool {
+		if weeks[i].Season == weeks[j].Season {
+			return weeks[i].Week < weeks[j].Week
+		}
+		return weeks[i].Season < weeks[j].Season
+	})
+	return weeks
+}
+
+// Get the season and week of the game, counting preseason weeks back from week 1
+func getComparisonWeek(gameStats PlayerStats) ComparisonWeek {
+	season := GetSeason(gameStats.GameDate)
+	week := GetWeek(gameStats.GameDate)
+	if week == 0 {
+		daysBeforeSeason := int(getSeasonStartDate(season).Sub(gameStats.GameDate).Hours() / 24)
+		week = -((daysBeforeSeason + 6) / 7)
+	}
+	return ComparisonWeek{Season: season, Week: week}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetComparisonWeek(t *testing.T) {
+	tests := []struct {
+		date string
+		expected ComparisonWeek
+	}{
+		// 2018 week 1 started on Tuesday September 4th
+		{"2018-09-06", ComparisonWeek{2018, 1}},
+		{"2018-09-16", ComparisonWeek{2018, 2}},
+		{"2018-08-30", ComparisonWeek{2018, -1}},
+		{"2018-08-28", ComparisonWeek{2018, -1}},
+		{"2018-08-23", ComparisonWeek{2018, -2}},
+		{"2019-02-03", ComparisonWeek{2018, 22}},
+		{"2019-09-08", ComparisonWeek{2019, 1}},
+	}
+
+	for _, test := range tests {
+		gameDate, _ := time.Parse("2006-01-02", test.date)
+		actual := getComparisonWeek(PlayerStats{GameDate: gameDate})
+		if actual != test.expected {
+			t.Errorf("getComparisonWeek(%v) = %v, expected %v", test.date, actual, test.expected)
+		}
+	}
+}
+
+func TestNewPlayerComparisonKeepsGamesFromDifferentSeasons(t *testing.T) {
+	gameDates := []string{"2017-09-10", "2018-09-09", "2018-08-23", "2018-08-30"}
+	var playerStats []PlayerStats
+	for _, date := range gameDates {
+		gameDate, _ := time.Parse("2006-01-02", date)
+		playerStats = append(playerStats, PlayerStats{PlayerId: 1, GameDate: gameDate, StatLine: StatLine{}})
+	}
+
+	comparison := NewPlayerComparison([]int{1}, playerStats, nil)
+	if len(comparison.Weeks) != len(gameDates) {
+		t.Fatalf("got %v weeks, expected %v", len(comparison.Weeks), len(gameDates))
+	}
+	for i, game := range comparison.Players[0].Weekly {
+		if game == nil {
+			t.Errorf("week %v has no game", comparison.Weeks[i])
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// A team's possession in a game, from the drives section of the feed
+type Drive struct {
+	GameKey string `json:"gameKey"`
+	DriveNum int `json:"driveNum"`
+	TeamAbbr string `json:"teamAbbr"`
+	StartQuarter int `json:"startQuarter"`
+	StartTime string `json:"startTime"`
+	StartYardLine string `json:"startYardLine"`
+	EndQuarter int `json:"endQuarter"`
+	EndTime string `json:"endTime"`
+	EndYardLine string `json:"endYardLine"`
+	Plays int `json:"plays"`
+	Yards int `json:"yards"`
+	FirstDowns int `json:"firstDowns"`
+	PenaltyYards int `json:"penaltyYards"`
+	// Time of possession in seconds
+	PossessionTime int `json:"possessionTime"`
+	IsRedZone bool `json:"isRedZone"`
+	Result string `json:"result"`
+	Points int `json:"points"`
+}
+
+// How well a team's drives turned into points over a set of games
+type DriveEfficiency struct {
+	TeamAbbr string `json:"teamAbbr"`
+	Drives int `json:"drives"`
+	Points int `json:"points"`
+	PointsPerDrive float64 `json:"pointsPerDrive"`
+	YardsPerDrive float64 `json:"yardsPerDrive"`
+	// Percentage of drives ending in a touchdown or field goal
+	ScorePct float64 `json:"scorePct"`
+	// Percentage of drives ending in an interception or lost fumble
+	TurnoverPct float64 `json:"turnoverPct"`
+}
+
+// Get the points a drive scored from its result. Touchdowns add the try after them,
+// which the feed only has in the play descriptions
+func GetDrivePoints(result string, playDescriptions []string) int {
+	switch strings.ToLower(result) {
+	case "touchdown":
+		points := 6
+		for _, description := range playDescriptions {
+			points += getTryPoints(description)
+		}
+		return points
+	case "field goal":
+		return 3
+	}
+	return 0
+}
+
+// Get the points scored by the try after a touchdown from a description of it, or 0 if it failed or the description
+// isn't of a try. Plays describe it in full, such as "S.Gostkowski extra point is GOOD, Center-J.Cardona,
+// Holder-R.Allen." or "TWO-POINT CONVERSION ATTEMPT. T.Brady pass to J.Edelman is complete. ATTEMPT SUCCEEDS."
+// The scoring summary puts it in brackets after the touchdown, such as "(S.Gostkowski kick)" or "(pass failed)"
+func getTryPoints(description string) int {
+	description = strings.ToLower(strings.TrimSpace(description))
+
+	if strings.Contains(description, "two-point conversion attempt") {
+		if strings.Contains(description, "attempt succeeds") {
+			return 2
+		}
+		return 0
+	}
+	if strings.Contains(description, "extra point is good") {
+		return 1
+	}
+
+	tryStart := strings.LastIndex(description, "(")
+	if !strings.HasSuffix(description, ")") || tryStart < 0 {
+		return 0
+	}
+	tryWords := strings.Fields(description[tryStart + 1:len(description) - 1])
+	for _, word := range tryWords {
+		if word == "failed" || word == "blocked" || word == "aborted" || word == "no" {
+			return 0
+		}
+	}
+	for _, word := range tryWords {
+		switch word {
+		case "kick":
+			return 1
+		case "pass", "run", "rush":
+			return 2
+		}
+	}
+	return 0
+}
+
+// Did the drive end in a touchdown or field goal?
+func (drive Drive) IsScore() bool {
+	result := strings.ToLower(drive.Result)
+	return result == "touchdown" || result == "field goal"
+}
+
+// Did the drive end in an interception or lost fumble?
+func (drive Drive) IsTurnover() bool {
+	result := strings.ToLower(drive.Result)
+	return strings.HasPrefix(result, "interception") || strings.HasPrefix(result, "fumble")
+}
+
+// Work out each team's drive efficiency from their drives, ordered by team
+func NewDriveEfficiencies(drives []Drive) []DriveEfficiency {
+	drivesByTeam := make(map[string][]Drive)
+	var teamAbbrs []string
+
+	for _, drive := range drives {
+		if _, exists := drivesByTeam[drive.TeamAbbr]; !exists {
+			teamAbbrs = append(teamAbbrs, drive.TeamAbbr)
+		}
+		drivesByTeam[drive.TeamAbbr] = append(drivesByTeam[drive.TeamAbbr], drive)
+	}
+
+	sort.Strings(teamAbbrs)
+	efficiencies := []DriveEfficiency{}
+	for _, teamAbbr := range teamAbbrs {
+		efficiencies = append(efficiencies, NewDriveEfficiency(drivesByTeam[teamAbbr]))
+	}
+	return efficiencies
+}
+
+// Work out a team's drive efficiency from the given drives
+func NewDriveEfficiency(drives []Drive) DriveEfficiency {
+	efficiency := DriveEfficiency{}
+	yards := 0
+	scores := 0
+	turnovers := 0
+
+	for _, drive := range drives {
+		efficiency.TeamAbbr = drive.TeamAbbr
+		efficiency.Drives++
+		efficiency.Points += drive.Points
+		yards += drive.Yards
+
+		if drive.IsScore() {
+			scores++
+		}
+		if drive.IsTurnover() {
+			turnovers++
+		}
+	}
+
+	if efficiency.Drives == 0 {
+		return efficiency
+	}
+
+	drivesCount := float64(efficiency.Drives)
+	efficiency.PointsPerDrive = roundToHundredth(float64(efficiency.Points) / drivesCount)
+	efficiency.YardsPerDrive = getRate(float64(yards), drivesCount)
+	efficiency.ScorePct = getPercentage(float64(scores), drivesCount)
+	efficiency.TurnoverPct = getPercentage(float64(turnovers), drivesCount)
+	return efficiency
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "testing"
+
+func TestGetTryPoints(t *testing.T) {
+	tests := []struct {
+		description string
+		expected int
+	}{
+		// Plays
+		{"S.Gostkowski extra point is GOOD, Center-J.Cardona, Holder-R.Allen.", 1},
+		{"S.Gostkowski extra point is No Good, Wide Right, Center-J.Cardona, Holder-R.Allen.", 0},
+		{"J.Tucker extra point is Blocked, Center-M.Cox, Holder-S.Koch.", 0},
+		{"(Pass formation) TWO-POINT CONVERSION ATTEMPT. T.Brady pass to J.Edelman is complete. ATTEMPT SUCCEEDS.", 2},
+		{"(Pass formation) TWO-POINT CONVERSION ATTEMPT. T.Brady pass to R.Gronkowski is incomplete. ATTEMPT FAILS.", 0},
+		{"(Run formation) TWO-POINT CONVERSION ATTEMPT. L.Blount rushes up the middle. ATTEMPT SUCCEEDS.", 2},
+		{"(1:05) T.Brady pass short right to R.Gronkowski for 5 yards, TOUCHDOWN.", 0},
+		{"(13:12) L.Blount up the middle to NE 34 for 4 yards (D.Revis).", 0},
+		// The scoring summary
+		{"R.Gronkowski 5 yd. pass from T.Brady (S.Gostkowski kick)", 1},
+		{"L.Blount 1 yd. run (S.Gostkowski kick failed)", 0},
+		{"L.Blount 1 yd. run (kick is no good)", 0},
+		{"J.Edelman 12 yd. pass from T.Brady (T.Brady-J.Edelman pass)", 2},
+		{"J.White 2 yd. run (J.White run)", 2},
+		{"J.White 2 yd. run (pass failed)", 0},
+		{"J.White 2 yd. run (run failed)", 0},
+	}
+
+	for _, test := range tests {
+		if points := getTryPoints(test.description); points != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.description, points, test.expected)
+		}
+	}
+}
+
+func TestGetDrivePoints(t *testing.T) {
+	touchdown := "(1:05) T.Brady pass short right to R.Gronkowski for 5 yards, TOUCHDOWN."
+
+	tests := []struct {
+		name string
+		result string
+		playDescriptions []string
+		expected int
+	}{
+		{"touchdown and extra point", "Touchdown",
+			[]string{touchdown, "S.Gostkowski extra point is GOOD, Center-J.Cardona, Holder-R.Allen."}, 7},
+		{"missed extra point", "Touchdown",
+			[]string{touchdown, "S.Gostkowski extra point is No Good, Wide Right, Center-J.Cardona, Holder-R.Allen."}, 6},
+		{"two point conversion", "Touchdown", []string{touchdown,
+			"(Pass formation) TWO-POINT CONVERSION ATTEMPT. T.Brady pass to J.Edelman is complete. ATTEMPT SUCCEEDS."}, 8},
+		{"failed two point conversion", "Touchdown", []string{touchdown,
+			"(Pass formation) TWO-POINT CONVERSION ATTEMPT. T.Brady pass to J.Edelman is incomplete. ATTEMPT FAILS."}, 6},
+		{"field goal", "Field Goal", []string{"S.Gostkowski 40 yard field goal is GOOD, Center-J.Cardona."}, 3},
+		{"punt", "Punt", []string{"R.Allen punts 45 yards to MIA 20, Center-J.Cardona, fair catch by J.Landry."}, 0},
+	}
+
+	for _, test := range tests {
+		if points := GetDrivePoints(test.result, test.playDescriptions); points != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, points, test.expected)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Every team starts at this rating, and ratings are pulled back towards it between seasons
+const averageEloRating = 1500
+
+// Settings for the Elo ratings
+type EloSettings struct {
+	// How far a single game can move a rating
+	KFactor float64 `json:"kFactor"`
+	// Rating points added to the home team when predicting a game
+	HomeFieldAdvantage float64 `json:"homeFieldAdvantage"`
+	// The fraction of the way back to the average a rating goes before each new season
+	SeasonRegression float64 `json:"seasonRegression"`
+}
+
+var DefaultEloSettings = EloSettings {
+	KFactor: 20,
+	HomeFieldAdvantage: 65,
+	SeasonRegression: 1.0 / 3,
+}
+
+// Teams that moved or changed their abbreviation keep their rating under the new one
+var renamedTeamAbbrs = map[string]string {
+	"JAC": "JAX",
+	"SD": "LAC",
+	"STL": "LA",
+	"OAK": "LV",
+}
+
+// How a game changed a team's rating
+type EloRatingChange struct {
+	Season int `json:"season"`
+	GameContext
+	// The team's chance of winning before the game
+	WinProbability float64 `json:"winProbability"`
+	RatingBefore float64 `json:"ratingBefore"`
+	RatingAfter float64 `json:"ratingAfter"`
+}
+
+// A team's current rating and how it got there
+type TeamEloRating struct {
+	TeamAbbr string `json:"teamAbbr"`
+	Rating float64 `json:"rating"`
+	History []EloRatingChange `json:"history"`
+}
+
+// Every team's rating after the finished games
+type EloRatings struct {
+	settings EloSettings
+	ratings map[string]float64
+	lastSeasons map[string]int
+	history map[string][]EloRatingChange
+}
+
+// A game's predicted result from the teams' ratings
+type GamePrediction struct {
+	Game
+	HomeRating float64 `json:"homeRating"`
+	AwayRating float64 `json:"awayRating"`
+	HomeWinProbability float64 `json:"homeWinProbability"`
+	// The home team's point spread, negative when they're favored
+	Spread float64 `json:"spread"`
+	Favorite string `json:"favorite"`
+}
+
+// Work out each team's rating from the results of the games finished by now, in the order they were played.
+// Preseason games don't count, and wins by more points move the ratings further
+func NewEloRatings(games []Game, settings EloSettings, now time.Time) EloRatings {
+	ratings := EloRatings {
+		settings: settings,
+		ratings: make(map[string]float64),
+		lastSeasons: make(map[string]int),
+		history: make(map[string][]EloRatingChange),
+	}
+
+	sortedGames := append([]Game{}, games...)
+	sort.SliceStable(sortedGames, func(i, j int) bool {
+		return sortedGames[i].GameDate.Before(sortedGames[j].GameDate)
+	})
+
+	for _, game := range sortedGames {
+		if game.Week < 1 || !game.IsFinished(now) {
+			continue
+		}
+
+		homeAbbr := getEloTeamAbbr(game.HomeAbbr)
+		awayAbbr := getEloTeamAbbr(game.AwayAbbr)
+		homeRating := ratings.GetRating(homeAbbr, game.Season)
+		awayRating := ratings.GetRating(awayAbbr, game.Season)
+		homeWinProbability := ratings.getHomeWinProbability(homeRating, awayRating)
+
+		// The winner's rating difference shrinks the change, so favorites aren't rewarded too much for blowouts
+		winnerRatingDifference := homeRating + settings.HomeFieldAdvantage - awayRating
+		if game.AwayScore > game.HomeScore {
+			winnerRatingDifference = -winnerRatingDifference
+		}
+		margin := math.Abs(float64(game.HomeScore - game.AwayScore))
+		marginMultiplier := math.Log(margin + 1) * 2.2 / (winnerRatingDifference * 0.001 + 2.2)
+		if margin == 0 {
+			marginMultiplier = 1
+		}
+
+		change := settings.KFactor * marginMultiplier * (getWinOutcome(game, game.HomeAbbr) - homeWinProbability)
+		ratings.addGame(game, game.HomeAbbr, homeRating, homeRating + change, homeWinProbability)
+		ratings.addGame(game, game.AwayAbbr, awayRating, awayRating - change, 1 - homeWinProbability)
+	}
+
+	return ratings
+}
+
+// Get the team's rating going into the given season. A team's first game of a season starts
+// from their rating regressed towards the average
+func (ratings EloRatings) GetRating(teamAbbr string, season int) float64 {
+	teamAbbr = getEloTeamAbbr(teamAbbr)
+	rating, ok := ratings.ratings[teamAbbr]
+	if !ok {
+		return averageEloRating
+	}
+
+	if season > ratings.lastSeasons[teamAbbr] {
+		rating -= (rating - averageEloRating) * ratings.settings.SeasonRegression
+	}
+	return rating
+}
+
+// Get every team's current rating and history, from the highest rated
+func (ratings EloRatings) GetTeamRatings() []TeamEloRating {
+	teamRatings := []TeamEloRating{}
+	for teamAbbr, rating := range ratings.ratings {
+		teamRatings = append(teamRatings, TeamEloRating {
+			TeamAbbr: teamAbbr,
+			Rating: roundToTenth(rating),
+			History: ratings.history[teamAbbr],
+		})
+	}
+
+	sort.Slice(teamRatings, func(i, j int) bool {
+		return teamRatings[i].Rating > teamRatings[j].Rating
+	})
+	return teamRatings
+}
+
+// Predict the game from the teams' current ratings
+func (ratings EloRatings) Predict(game Game) GamePrediction {
+	homeRating := ratings.GetRating(game.HomeAbbr, game.Season)
+	awayRating := ratings.GetRating(game.AwayAbbr, game.Season)
+	homeWinProbability := ratings.getHomeWinProbability(homeRating, awayRating)
+
+	// Every 25 rating points is worth about a point on the field. Spreads are rounded to the half point
+	ratingDifference := homeRating + ratings.settings.HomeFieldAdvantage - awayRating
+	spread := math.Round(-ratingDifference / 25 * 2) / 2
+
+	favorite := game.HomeAbbr
+	if homeWinProbability < 0.5 {
+		favorite = game.AwayAbbr
+	}
+
+	return GamePrediction {
+		Game: game,
+		HomeRating: roundToTenth(homeRating),
+		AwayRating: roundToTenth(awayRating),
+		HomeWinProbability: math.Round(homeWinProbability * 1000) / 1000,
+		Spread: spread,
+		Favorite: favorite,
+	}
+}
+
+func (ratings EloRatings) getHomeWinProbability(homeRating float64, awayRating float64) float64 {
+	ratingDifference := homeRating + ratings.settings.HomeFieldAdvantage - awayRating
+	return 1 / (math.Pow(10, -ratingDifference / 400) + 1)
+}
+
+// Record the game in the team's history and move their rating
+func (ratings EloRatings) addGame(game Game, teamAbbr string, ratingBefore float64, ratingAfter float64,
+	winProbability float64) {
+	eloTeamAbbr := getEloTeamAbbr(teamAbbr)
+	ratings.ratings[eloTeamAbbr] = ratingAfter
+	ratings.lastSeasons[eloTeamAbbr] = game.Season
+	ratings.history[eloTeamAbbr] = append(ratings.history[eloTeamAbbr], EloRatingChange {
+		Season: game.Season,
+		GameContext: NewGameContext(game, teamAbbr),
+		WinProbability: math.Round(winProbability * 1000) / 1000,
+		RatingBefore: roundToTenth(ratingBefore),
+		RatingAfter: roundToTenth(ratingAfter),
+	})
+}
+
+func getEloTeamAbbr(teamAbbr string) string {
+	if renamedAbbr, ok := renamedTeamAbbrs[teamAbbr]; ok {
+		return renamedAbbr
+	}
+	return teamAbbr
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewEloRatings(t *testing.T) {
+	preseasonGame := NewGame("2018080900", time.Date(2018, time.August, 9, 0, 0, 0, 0, time.UTC), "NE", "WAS", 26, 17)
+	unplayedGame := newTestGame(2021, 1, "NE", "MIA")
+
+	tests := []struct {
+		name string
+		games []Game
+		teamAbbr string
+		season int
+		expectedRating float64
+	}{
+		{"no games", nil, "NE", 2018, 1500},
+		{"preseason games don't count", []Game{preseasonGame}, "NE", 2018, 1500},
+		{"games after now don't count", []Game{unplayedGame}, "NE", 2021, 1500},
+		// Even teams with the home team winning by 14: 20 * ln(15) * 2.2 / (65 * 0.001 + 2.2) * (1 - 0.5925)
+		{"home win", []Game{newTestGame(2018, 1, "NE", "MIA")}, "NE", 2018, 1521.4},
+		{"away loss", []Game{newTestGame(2018, 1, "NE", "MIA")}, "MIA", 2018, 1478.6},
+		// A third of the way back to 1500 before the next season
+		{"season regression", []Game{newTestGame(2018, 1, "NE", "MIA")}, "NE", 2019, 1514.3},
+		{"renamed team keeps its rating", []Game{newTestGame(2019, 1, "OAK", "KC")}, "LV", 2019, 1521.4},
+		{"renamed team is rated by its new name", []Game{newTestGame(2019, 1, "OAK", "KC")}, "OAK", 2019, 1521.4},
+	}
+
+	for _, test := range tests {
+		ratings := NewEloRatings(test.games, DefaultEloSettings, testNow)
+		rating := roundToTenth(ratings.GetRating(test.teamAbbr, test.season))
+		if rating != test.expectedRating {
+			t.Errorf("%v: got %v for %v, expected %v", test.name, rating, test.teamAbbr, test.expectedRating)
+		}
+	}
+}
+
+func TestNewEloRatingsHistory(t *testing.T) {
+	ratings := NewEloRatings([]Game{newTestGame(2019, 1, "OAK", "KC")}, DefaultEloSettings, testNow)
+	teamRatings := ratings.GetTeamRatings()
+	if len(teamRatings) != 2 || teamRatings[0].TeamAbbr != "LV" || teamRatings[1].TeamAbbr != "KC" {
+		t.Fatalf("got %+v, expected LV then KC", teamRatings)
+	}
+
+	history := teamRatings[0].History
+	if len(history) != 1 || history[0].Opponent != "KC" || history[0].RatingBefore != 1500 ||
+		history[0].RatingAfter != teamRatings[0].Rating {
+		t.Errorf("got history %+v for LV", history)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	ratings := NewEloRatings([]Game{
+		newTestGame(2018, 1, "NE", "MIA"),
+		newTestGame(2018, 2, "NE", "BUF"),
+		newTestGame(2018, 3, "NE", "NYJ"),
+	}, DefaultEloSettings, testNow)
+
+	tests := []struct {
+		homeAbbr string
+		awayAbbr string
+		expectedFavorite string
+		expectedSpread float64
+	}{
+		// 65 points of home field is worth 2.6 points, rounded to the half point
+		{"KC", "DEN", "KC", -2.5},
+		{"MIA", "NE", "NE", 0.5},
+		{"NE", "DEN", "NE", -5},
+	}
+
+	for _, test := range tests {
+		prediction := ratings.Predict(Game{Season: 2018, HomeAbbr: test.homeAbbr, AwayAbbr: test.awayAbbr})
+		if prediction.Favorite != test.expectedFavorite || prediction.Spread != test.expectedSpread {
+			t.Errorf("%v vs %v: got %v by %v, expected %v by %v", test.homeAbbr, test.awayAbbr,
+				prediction.Favorite, prediction.Spread, test.expectedFavorite, test.expectedSpread)
+		}
+
+		expectedProbability := 1 / (math.Pow(10, -(prediction.HomeRating + 65 - prediction.AwayRating) / 400) + 1)
+		if math.Abs(prediction.HomeWinProbability - expectedProbability) > 0.001 {
+			t.Errorf("%v vs %v: got a home win probability of %v, expected %v", test.homeAbbr, test.awayAbbr,
+				prediction.HomeWinProbability, expectedProbability)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Buckets need this many plays before their average is used, otherwise a wider bucket is used
+const minExpectedPointsSamples = 20
+
+// Expected points for the offense from the down, distance and field position of a play, fitted from the
+// points of the next score in the same half of every ingested play
+type ExpectedPointsModel struct {
+	buckets map[string]*expectedPointsBucket
+}
+
+// The next score values of the plays in a bucket
+type expectedPointsBucket struct {
+	total float64
+	count int
+}
+
+// A play with the expected points before and after it
+type PlayEpa struct {
+	Play
+	ExpectedPointsBefore float64 `json:"expectedPointsBefore"`
+	ExpectedPointsAfter float64 `json:"expectedPointsAfter"`
+	Epa float64 `json:"epa"`
+}
+
+// A player's expected points added over their plays as a passer, rusher or receiver
+type PlayerEpa struct {
+	PlayerId int `json:"playerId"`
+	Name string `json:"name"`
+	TeamAbbr string `json:"teamAbbr"`
+	Plays int `json:"plays"`
+	Epa float64 `json:"epa"`
+	EpaPerPlay float64 `json:"epaPerPlay"`
+	// Expected points added keyed by the player's part in the play
+	EpaByRole map[string]float64 `json:"epaByRole"`
+}
+
+// A team's expected points added on offense, and allowed on defense
+type TeamEpa struct {
+	TeamAbbr string `json:"teamAbbr"`
+	OffensePlays int `json:"offensePlays"`
+	OffenseEpa float64 `json:"offenseEpa"`
+	OffenseEpaPerPlay float64 `json:"offenseEpaPerPlay"`
+	DefensePlays int `json:"defensePlays"`
+	DefenseEpa float64 `json:"defenseEpa"`
+	DefenseEpaPerPlay float64 `json:"defenseEpaPerPlay"`
+}
+
+// Fit the expected points model from the given plays and the scoring plays in their games
+func NewExpectedPointsModel(plays []Play, scoringPlays []ScoringPlay) ExpectedPointsModel {
+	model := ExpectedPointsModel {
+		buckets: make(map[string]*expectedPointsBucket),
+	}
+
+	scoringPlaysByKey := groupScoringPlaysByPlay(scoringPlays)
+	for _, gamePlays := range groupPlaysByGame(plays) {
+		// Go backwards through the game so the next score in the half is known at each play
+		half := 0
+		var nextScore *ScoringPlay
+		for i := len(gamePlays) - 1; i >= 0; i-- {
+			play := gamePlays[i]
+			if play.GetHalf() != half {
+				half = play.GetHalf()
+				nextScore = nil
+			}
+			if scoringPlay, ok := scoringPlaysByKey[GetPlayKey(play.GameKey, play.PlayId)]; ok {
+				nextScore = &scoringPlay
+			}
+
+			if !play.IsScrimmage() {
+				continue
+			}
+
+			value := 0.0
+			if nextScore != nil {
+				value = getScoringPlayValue(*nextScore, play.TeamAbbr)
+			}
+			for _, key := range getExpectedPointsBucketKeys(play.Down, play.YardsToGo, play.FieldPosition) {
+				model.addToBucket(key, value)
+			}
+		}
+	}
+
+	return model
+}
+
+// Get the expected points for the offense given the down, yards to go and yards from their own goal line
+func (model ExpectedPointsModel) GetExpectedPoints(down int, yardsToGo int, fieldPosition int) float64 {
+	for _, key := range getExpectedPointsBucketKeys(down, yardsToGo, fieldPosition) {
+		bucket, ok := model.buckets[key]
+		if ok && bucket.count >= minExpectedPointsSamples {
+			return roundToHundredth(bucket.total / float64(bucket.count))
+		}
+	}
+
+	// Without enough history, go from -2 at the offense's own goal line to 6 at the opponent's
+	return roundToHundredth(-2 + float64(fieldPosition) * 0.08)
+}
+
+// Get the expected points added by each play from scrimmage in the given plays, in game and play order
+func NewPlayEpas(model ExpectedPointsModel, plays []Play, scoringPlays []ScoringPlay) []PlayEpa {
+	playEpas := []PlayEpa{}
+	scoringPlaysByKey := groupScoringPlaysByPlay(scoringPlays)
+
+	for _, gamePlays := range groupPlaysByGame(plays) {
+		for i, play := range gamePlays {
+			if !play.IsScrimmage() {
+				continue
+			}
+
+			playEpa := PlayEpa {
+				Play: play,
+				ExpectedPointsBefore: model.GetExpectedPoints(play.Down, play.YardsToGo, play.FieldPosition),
+			}
+
+			if scoringPlay, ok := scoringPlaysByKey[GetPlayKey(play.GameKey, play.PlayId)]; ok {
+				playEpa.ExpectedPointsAfter = getScoringPlayValue(scoringPlay, play.TeamAbbr)
+			} else if nextPlay, ok := getNextScrimmagePlay(gamePlays, i); ok {
+				playEpa.ExpectedPointsAfter = model.GetExpectedPoints(nextPlay.Down, nextPlay.YardsToGo,
+					nextPlay.FieldPosition)
+				if nextPlay.TeamAbbr != play.TeamAbbr {
+					playEpa.ExpectedPointsAfter = -playEpa.ExpectedPointsAfter
+				}
+			}
+
+			playEpa.Epa = roundToHundredth(playEpa.ExpectedPointsAfter - playEpa.ExpectedPointsBefore)
+			playEpas = append(playEpas, playEpa)
+		}
+	}
+
+	return playEpas
+}
+
+// Add up the expected points added by each passer, rusher and receiver, ordered from most to least
+func NewPlayerEpas(playEpas []PlayEpa) []PlayerEpa {
+	playerEpasByKey := make(map[string]*PlayerEpa)
+	var playerKeys []string
+
+	for _, playEpa := range playEpas {
+		for _, player := range playEpa.GetOffensivePlayers() {
+			playerEpa, exists := playerEpasByKey[player.PlayerKey]
+			if !exists {
+				playerEpa = &PlayerEpa {
+					EpaByRole: make(map[string]float64),
+				}
+				playerEpasByKey[player.PlayerKey] = playerEpa
+				playerKeys = append(playerKeys, player.PlayerKey)
+			}
+
+			playerEpa.PlayerId = player.PlayerId
+			playerEpa.Name = player.Name
+			playerEpa.TeamAbbr = player.TeamAbbr
+			playerEpa.Plays++
+			playerEpa.Epa += playEpa.Epa
+			playerEpa.EpaByRole[player.GetRole()] += playEpa.Epa
+		}
+	}
+
+	playerEpas := []PlayerEpa{}
+	for _, playerKey := range playerKeys {
+		playerEpa := *playerEpasByKey[playerKey]
+		playerEpa.Epa = roundToHundredth(playerEpa.Epa)
+		playerEpa.EpaPerPlay = roundToHundredth(playerEpa.Epa / float64(playerEpa.Plays))
+		for role, epa := range playerEpa.EpaByRole {
+			playerEpa.EpaByRole[role] = roundToHundredth(epa)
+		}
+		playerEpas = append(playerEpas, playerEpa)
+	}
+
+	sort.SliceStable(playerEpas, func(i, j int) bool {
+		return playerEpas[i].Epa > playerEpas[j].Epa
+	})
+	return playerEpas
+}
+
+// Add up the expected points added by each team on offense and allowed on defense, ordered by team
+func NewTeamEpas(playEpas []PlayEpa) []TeamEpa {
+	teamEpasByAbbr := make(map[string]*TeamEpa)
+	getTeamEpa := func(teamAbbr string) *TeamEpa {
+		if _, exists := teamEpasByAbbr[teamAbbr]; !exists {
+			teamEpasByAbbr[teamAbbr] = &TeamEpa{TeamAbbr: teamAbbr}
+		}
+		return teamEpasByAbbr[teamAbbr]
+	}
+
+	for _, playEpa := range playEpas {
+		offense := getTeamEpa(playEpa.TeamAbbr)
+		offense.OffensePlays++
+		offense.OffenseEpa += playEpa.Epa
+
+		if len(playEpa.DefenseAbbr) > 0 {
+			defense := getTeamEpa(playEpa.DefenseAbbr)
+			defense.DefensePlays++
+			defense.DefenseEpa += playEpa.Epa
+		}
+	}
+
+	var teamAbbrs []string
+	for teamAbbr := range teamEpasByAbbr {
+		teamAbbrs = append(teamAbbrs, teamAbbr)
+	}
+	sort.Strings(teamAbbrs)
+
+	teamEpas := []TeamEpa{}
+	for _, teamAbbr := range teamAbbrs {
+		teamEpa := *teamEpasByAbbr[teamAbbr]
+		teamEpa.OffenseEpa = roundToHundredth(teamEpa.OffenseEpa)
+		teamEpa.DefenseEpa = roundToHundredth(teamEpa.DefenseEpa)
+		if teamEpa.OffensePlays > 0 {
+			teamEpa.OffenseEpaPerPlay = roundToHundredth(teamEpa.OffenseEpa / float64(teamEpa.OffensePlays))
+		}
+		if teamEpa.DefensePlays > 0 {
+			teamEpa.DefenseEpaPerPlay = roundToHundredth(teamEpa.DefenseEpa / float64(teamEpa.DefensePlays))
+		}
+		teamEpas = append(teamEpas, teamEpa)
+	}
+	return teamEpas
+}
+
+func (model ExpectedPointsModel) addToBucket(key string, value float64) {
+	bucket, ok := model.buckets[key]
+	if !ok {
+		bucket = &expectedPointsBucket{}
+		model.buckets[key] = bucket
+	}
+	bucket.total += value
+	bucket.count++
+}
+
+// Get the bucket keys for a play's situation, from the most specific to the widest:
+// down, distance and field position, then down and field position, then field position
+func getExpectedPointsBucketKeys(down int, yardsToGo int, fieldPosition int) []string {
+	fieldBucket := fieldPosition / 10
+	if fieldBucket > 9 {
+		fieldBucket = 9
+	}
+
+	return []string {
+		fmt.Sprintf("%v-%v-%v", down, getDistanceBucket(yardsToGo), fieldBucket),
+		fmt.Sprintf("%v-%v", down, fieldBucket),
+		fmt.Sprintf("%v", fieldBucket),
+	}
+}
+
+// Get the distance bucket for the yards to go: short, medium, long or very long
+func getDistanceBucket(yardsToGo int) string {
+	switch {
+	case yardsToGo <= 3:
+		return "short"
+	case yardsToGo <= 6:
+		return "medium"
+	case yardsToGo <= 10:
+		return "long"
+	}
+	return "veryLong"
+}
+
+// Get the points of a scoring play from the given team's point of view
+func getScoringPlayValue(scoringPlay ScoringPlay, teamAbbr string) float64 {
+	if scoringPlay.TeamAbbr == teamAbbr {
+		return float64(scoringPlay.Points)
+	}
+	return -float64(scoringPlay.Points)
+}
+
+// Get the next play from scrimmage after the given index in the same half
+func getNextScrimmagePlay(gamePlays []Play, index int) (Play, bool) {
+	for _, play := range gamePlays[index + 1:] {
+		if play.GetHalf() != gamePlays[index].GetHalf() {
+			break
+		}
+		if play.IsScrimmage() {
+			return play, true
+		}
+	}
+	return Play{}, false
+}
+
+// Group the plays by game, with each game's plays in order and the games ordered by key
+func groupPlaysByGame(plays []Play) [][]Play {
+	playsByGame := make(map[string][]Play)
+	var gameKeys []string
+
+	for _, play := range plays {
+		if _, exists := playsByGame[play.GameKey]; !exists {
+			gameKeys = append(gameKeys, play.GameKey)
+		}
+		playsByGame[play.GameKey] = append(playsByGame[play.GameKey], play)
+	}
+
+	sort.Strings(gameKeys)
+	var games [][]Play
+	for _, gameKey := range gameKeys {
+		gamePlays := playsByGame[gameKey]
+		sort.SliceStable(gamePlays, func(i, j int) bool {
+			return gamePlays[i].PlayId < gamePlays[j].PlayId
+		})
+		games = append(games, gamePlays)
+	}
+	return games
+}
+
+// Key the scoring plays by their game and play id
+func groupScoringPlaysByPlay(scoringPlays []ScoringPlay) map[string]ScoringPlay {
+	scoringPlaysByKey := make(map[string]ScoringPlay)
+	for _, scoringPlay := range scoringPlays {
+		scoringPlaysByKey[GetPlayKey(scoringPlay.GameKey, scoringPlay.PlayId)] = scoringPlay
+	}
+	return scoringPlaysByKey
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Games where NE doesn't score in the first half, scores a TD in the third quarter,
+// and gets the ball back from MIA in the fourth to kick a field goal
+func newTestExpectedPointsGames(gameCount int) ([]Play, []ScoringPlay) {
+	var plays []Play
+	var scoringPlays []ScoringPlay
+	for i := 0; i < gameCount; i++ {
+		gameKey := fmt.Sprintf("20180909%02d", i)
+		plays = append(plays,
+			Play{GameKey: gameKey, PlayId: 1, TeamAbbr: "NE", Quarter: 2, Down: 1, YardsToGo: 10, FieldPosition: 25},
+			Play{GameKey: gameKey, PlayId: 2, TeamAbbr: "NE", Quarter: 3, Down: 1, YardsToGo: 10, FieldPosition: 35},
+			Play{GameKey: gameKey, PlayId: 3, TeamAbbr: "MIA", Quarter: 4, Down: 1, YardsToGo: 10, FieldPosition: 60},
+			Play{GameKey: gameKey, PlayId: 4, TeamAbbr: "NE", Quarter: 4, Down: 1, YardsToGo: 10, FieldPosition: 75},
+		)
+		scoringPlays = append(scoringPlays,
+			ScoringPlay{GameKey: gameKey, PlayId: 2, TeamAbbr: "NE", Points: 7},
+			ScoringPlay{GameKey: gameKey, PlayId: 4, TeamAbbr: "NE", Points: 3},
+		)
+	}
+	return plays, scoringPlays
+}
+
+func TestGetExpectedPoints(t *testing.T) {
+	model := NewExpectedPointsModel(newTestExpectedPointsGames(minExpectedPointsSamples))
+	smallModel := NewExpectedPointsModel(newTestExpectedPointsGames(minExpectedPointsSamples - 1))
+
+	tests := []struct {
+		name string
+		model ExpectedPointsModel
+		down int
+		yardsToGo int
+		fieldPosition int
+		expected float64
+	}{
+		{"the next score is in the other half", model, 1, 10, 25, 0},
+		{"the offense scores next", model, 1, 10, 35, 7},
+		{"the defense scores next", model, 1, 10, 60, -3},
+		{"the scoring play counts itself", model, 1, 10, 75, 3},
+		{"down and field position when the distance is new", model, 1, 2, 39, 7},
+		{"field position when the down is new", model, 3, 10, 35, 7},
+		{"a line from -2 to 6 for a new field position", model, 1, 10, 55, 2.4},
+		{"a line from -2 to 6 without enough plays", smallModel, 1, 10, 35, 0.8},
+		{"a line from -2 to 6 with no plays", ExpectedPointsModel{}, 1, 10, 100, 6},
+	}
+
+	for _, test := range tests {
+		expectedPoints := test.model.GetExpectedPoints(test.down, test.yardsToGo, test.fieldPosition)
+		if expectedPoints != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, expectedPoints, test.expected)
+		}
+	}
+}
+
+func TestNewPlayEpas(t *testing.T) {
+	newPlayer := func(playerKey string, teamAbbr string, statId int) PlayPlayer {
+		return PlayPlayer{PlayerKey: playerKey, Name: playerKey, TeamAbbr: teamAbbr, StatId: statId}
+	}
+	plays := []Play{
+		{GameKey: "2018090900", PlayId: 1, TeamAbbr: "NE", DefenseAbbr: "MIA", Quarter: 1, Down: 1, YardsToGo: 10,
+			FieldPosition: 25, Players: []PlayPlayer{newPlayer("brady", "NE", statIdCompletePass),
+			newPlayer("gronk", "NE", statIdReception), newPlayer("tackler", "MIA", 79)}},
+		// A kickoff between the plays doesn't count
+		{GameKey: "2018090900", PlayId: 2, Quarter: 1},
+		{GameKey: "2018090900", PlayId: 3, TeamAbbr: "NE", DefenseAbbr: "MIA", Quarter: 1, Down: 1, YardsToGo: 10,
+			FieldPosition: 45, Players: []PlayPlayer{newPlayer("michel", "NE", statIdRush)}},
+		{GameKey: "2018090900", PlayId: 4, TeamAbbr: "MIA", DefenseAbbr: "NE", Quarter: 1, Down: 1, YardsToGo: 10,
+			FieldPosition: 50},
+		{GameKey: "2018090900", PlayId: 5, TeamAbbr: "MIA", DefenseAbbr: "NE", Quarter: 1, Down: 2, YardsToGo: 5,
+			FieldPosition: 80, Players: []PlayPlayer{newPlayer("drake", "MIA", statIdRushTd)}},
+	}
+	scoringPlays := []ScoringPlay{{GameKey: "2018090900", PlayId: 5, TeamAbbr: "MIA", Points: 7}}
+
+	// Without history the expected points are -2 + 0.08 per yard from the offense's own goal line
+	playEpas := NewPlayEpas(ExpectedPointsModel{}, plays, scoringPlays)
+	var epas []float64
+	for _, playEpa := range playEpas {
+		epas = append(epas, playEpa.Epa)
+	}
+	// 0 to 1.6, 1.6 to the -2 of MIA's ball at midfield, 2 to 4.4, then 4.4 to the touchdown's 7
+	if !reflect.DeepEqual(epas, []float64{1.6, -3.6, 2.4, 2.6}) {
+		t.Fatalf("got epas %v, expected 1.6, -3.6, 2.4 and 2.6", epas)
+	}
+
+	var playerEpas []string
+	for _, playerEpa := range NewPlayerEpas(playEpas) {
+		playerEpas = append(playerEpas, fmt.Sprintf("%v %v %v", playerEpa.Name, playerEpa.Plays, playerEpa.Epa))
+	}
+	expectedPlayerEpas := []string{"drake 1 2.6", "brady 1 1.6", "gronk 1 1.6", "michel 1 -3.6"}
+	if !reflect.DeepEqual(playerEpas, expectedPlayerEpas) {
+		t.Errorf("got player epas %v, expected %v", playerEpas, expectedPlayerEpas)
+	}
+
+	expectedTeamEpas := []TeamEpa{
+		{TeamAbbr: "MIA", OffensePlays: 2, OffenseEpa: 5, OffenseEpaPerPlay: 2.5, DefensePlays: 2, DefenseEpa: -2,
+			DefenseEpaPerPlay: -1},
+		{TeamAbbr: "NE", OffensePlays: 2, OffenseEpa: -2, OffenseEpaPerPlay: -1, DefensePlays: 2, DefenseEpa: 5,
+			DefenseEpaPerPlay: 2.5},
+	}
+	if teamEpas := NewTeamEpas(playEpas); !reflect.DeepEqual(teamEpas, expectedTeamEpas) {
+		t.Errorf("got team epas %+v, expected %+v", teamEpas, expectedTeamEpas)
+	}
+}
@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// The result and context of a game between two teams
+type Game struct {
+	GameKey string `json:"gameKey"`
+	GameDate time.Time `json:"gameDate"`
+	Season int `json:"season"`
+	Week int `json:"week"`
+	HomeAbbr string `json:"homeAbbr"`
+	AwayAbbr string `json:"awayAbbr"`
+	HomeScore int `json:"homeScore"`
+	AwayScore int `json:"awayScore"`
+}
+
+// A game from one team's point of view
+type GameContext struct {
+	GameKey string `json:"gameKey"`
+	Week int `json:"week"`
+	Opponent string `json:"opponent"`
+	IsHome bool `json:"isHome"`
+	Result string `json:"result"`
+	TeamScore int `json:"teamScore"`
+	OpponentScore int `json:"opponentScore"`
+}
+
+func NewGame(gameKey string, gameDate time.Time, homeAbbr string, awayAbbr string,
+	homeScore int, awayScore int) Game {
+	return Game {
+		GameKey: gameKey,
+		GameDate: gameDate,
+		Season: GetSeason(gameDate),
+		Week: GetWeek(gameDate),
+		HomeAbbr: homeAbbr,
+		AwayAbbr: awayAbbr,
+		HomeScore: homeScore,
+		AwayScore: awayScore,
+	}
+}
+
+// Does the team play in the game?
+func (game Game) HasTeam(teamAbbr string) bool {
+	return game.HomeAbbr == teamAbbr || game.AwayAbbr == teamAbbr
+}
+
+// Is the game in the regular season, rather than the preseason or playoffs?
+func (game Game) IsRegularSeason() bool {
+	return game.Week >= 1 && game.Week <= GetRegularSeasonWeeks(game.Season)
+}
+
+// Has the game been played by the given time? The feed is only read up to today, so today's games may not be over
+func (game Game) IsFinished(now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return game.GameDate.Before(today)
+}
+
+// Get the opponent of the given team in the game
+func (game Game) GetOpponent(teamAbbr string) string {
+	if game.HomeAbbr == teamAbbr {
+		return game.AwayAbbr
+	}
+	return game.HomeAbbr
+}
+
+// Get the given team's score and their opponent's score
+func (game Game) GetScores(teamAbbr string) (int, int) {
+	if game.HomeAbbr == teamAbbr {
+		return game.HomeScore, game.AwayScore
+	}
+	return game.AwayScore, game.HomeScore
+}
+
+// Get the result of the game for the given team; W, L or T
+func (game Game) GetResult(teamAbbr string) string {
+	teamScore, opponentScore := game.GetScores(teamAbbr)
+	if teamScore > opponentScore {
+		return "W"
+	}
+	if teamScore < opponentScore {
+		return "L"
+	}
+	return "T"
+}
+
+// Get the game from the given team's point of view
+func NewGameContext(game Game, teamAbbr string) GameContext {
+	teamScore, opponentScore := game.GetScores(teamAbbr)
+	return GameContext {
+		GameKey: game.GameKey,
+		Week: game.Week,
+		Opponent: game.GetOpponent(teamAbbr),
+		IsHome: game.HomeAbbr == teamAbbr,
+		Result: game.GetResult(teamAbbr),
+		TeamScore: teamScore,
+		OpponentScore: opponentScore,
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// A 24-10 home win on the Sunday of the given week
+func newTestGame(season int, week int, winnerAbbr string, loserAbbr string) Game {
+	weekStart, _ := GetWeekDateRange(season, week)
+	return NewGame(fmt.Sprintf("%v-%v-%v", week, winnerAbbr, loserAbbr), weekStart.AddDate(0, 0, 5),
+		winnerAbbr, loserAbbr, 24, 10)
+}
+
+func TestIsFinished(t *testing.T) {
+	game := NewGame("2018090900", time.Date(2018, time.September, 9, 0, 0, 0, 0, time.UTC), "NE", "HOU", 27, 20)
+
+	tests := []struct {
+		name string
+		now time.Time
+		expected bool
+	}{
+		{"before the game", time.Date(2018, time.September, 8, 12, 0, 0, 0, time.UTC), false},
+		{"on the day of the game", time.Date(2018, time.September, 9, 23, 0, 0, 0, time.UTC), false},
+		{"the day after", time.Date(2018, time.September, 10, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, test := range tests {
+		if isFinished := game.IsFinished(test.now); isFinished != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, isFinished, test.expected)
+		}
+	}
+}
+
+func TestNewGameContext(t *testing.T) {
+	game := NewGame("2018090900", time.Date(2018, time.September, 9, 0, 0, 0, 0, time.UTC), "NE", "HOU", 27, 20)
+	tie := NewGame("2018091600", time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC), "HOU", "NYG", 17, 17)
+
+	tests := []struct {
+		name string
+		game Game
+		teamAbbr string
+		expected GameContext
+	}{
+		{"home win", game, "NE", GameContext{"2018090900", 1, "HOU", true, "W", 27, 20}},
+		{"away loss", game, "HOU", GameContext{"2018090900", 1, "NE", false, "L", 20, 27}},
+		{"tie", tie, "NYG", GameContext{"2018091600", 2, "HOU", false, "T", 17, 17}},
+	}
+
+	for _, test := range tests {
+		if context := NewGameContext(test.game, test.teamAbbr); context != test.expected {
+			t.Errorf("%v: got %+v, expected %+v", test.name, context, test.expected)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// A scoring play from the scoring summary section of the feed
+type ScoringPlay struct {
+	GameKey string `json:"gameKey"`
+	PlayId int `json:"playId"`
+	TeamAbbr string `json:"teamAbbr"`
+	Quarter int `json:"quarter"`
+	// TD, FG or SAF
+	Type string `json:"type"`
+	Description string `json:"description"`
+	// The feed's id for the scorer
+	ScorerKey string `json:"-"`
+	Scorer string `json:"scorer"`
+	Points int `json:"points"`
+	// The score after the play
+	HomeScore int `json:"homeScore"`
+	AwayScore int `json:"awayScore"`
+}
+
+// A team's points in each quarter of a game
+type TeamLinescore struct {
+	GameKey string `json:"gameKey"`
+	TeamAbbr string `json:"teamAbbr"`
+	// Points in quarters 1 to 4
+	Quarters []int `json:"quarters"`
+	Overtime int `json:"overtime"`
+	Total int `json:"total"`
+}
+
+// A game's result with its linescore and scoring plays, for judging the game script
+type GameDetail struct {
+	Game
+	Linescores []TeamLinescore `json:"linescores"`
+	ScoringPlays []ScoringPlay `json:"scoringPlays"`
+	// The biggest lead each team had, keyed by team
+	LargestLeads map[string]int `json:"largestLeads"`
+	// The final margin of victory
+	Margin int `json:"margin"`
+}
+
+// Get a team's linescore from their points keyed by quarter, with 5 for overtime
+func NewTeamLinescore(gameKey string, teamAbbr string, quarterPoints map[int]int) TeamLinescore {
+	linescore := TeamLinescore {
+		GameKey: gameKey,
+		TeamAbbr: teamAbbr,
+		Quarters: make([]int, 4),
+	}
+
+	for quarter, points := range quarterPoints {
+		if quarter >= 1 && quarter <= 4 {
+			linescore.Quarters[quarter - 1] = points
+		} else if quarter > 4 {
+			linescore.Overtime += points
+		}
+		linescore.Total += points
+	}
+	return linescore
+}
+
+// Get the points a scoring play is worth from its type. Touchdowns include the try after them from the description
+func GetScoringPlayPoints(scoringType string, description string) int {
+	switch strings.ToUpper(scoringType) {
+	case "TD":
+		return 6 + getTryPoints(description)
+	case "FG":
+		return 3
+	case "SAF":
+		return 2
+	}
+	return 0
+}
+
+// Put together a game's detail, with the linescores home team first and the scoring plays in order
+// with the running score after each
+func NewGameDetail(game Game, linescores []TeamLinescore, scoringPlays []ScoringPlay) GameDetail {
+	detail := GameDetail {
+		Game: game,
+		Linescores: []TeamLinescore{},
+		ScoringPlays: []ScoringPlay{},
+		LargestLeads: map[string]int {
+			game.HomeAbbr: 0,
+			game.AwayAbbr: 0,
+		},
+	}
+
+	for _, linescore := range linescores {
+		if linescore.TeamAbbr == game.HomeAbbr {
+			detail.Linescores = append([]TeamLinescore{linescore}, detail.Linescores...)
+			continue
+		}
+		detail.Linescores = append(detail.Linescores, linescore)
+	}
+
+	sort.Slice(scoringPlays, func(i, j int) bool {
+		return scoringPlays[i].PlayId < scoringPlays[j].PlayId
+	})
+
+	homeScore := 0
+	awayScore := 0
+	for _, scoringPlay := range scoringPlays {
+		// Safeties are credited to the defense, which is the team in the scoring summary
+		if scoringPlay.TeamAbbr == game.HomeAbbr {
+			homeScore += scoringPlay.Points
+		} else {
+			awayScore += scoringPlay.Points
+		}
+
+		scoringPlay.HomeScore = homeScore
+		scoringPlay.AwayScore = awayScore
+		detail.ScoringPlays = append(detail.ScoringPlays, scoringPlay)
+
+		if homeScore - awayScore > detail.LargestLeads[game.HomeAbbr] {
+			detail.LargestLeads[game.HomeAbbr] = homeScore - awayScore
+		}
+		if awayScore - homeScore > detail.LargestLeads[game.AwayAbbr] {
+			detail.LargestLeads[game.AwayAbbr] = awayScore - homeScore
+		}
+	}
+
+	detail.Margin = game.HomeScore - game.AwayScore
+	if detail.Margin < 0 {
+		detail.Margin = -detail.Margin
+	}
+	return detail
+}
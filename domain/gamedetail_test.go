@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetScoringPlayPoints(t *testing.T) {
+	tests := []struct {
+		scoringType string
+		description string
+		expected int
+	}{
+		{"TD", "R.Gronkowski 5 yd. pass from T.Brady (S.Gostkowski kick)", 7},
+		{"TD", "L.Blount 1 yd. run (S.Gostkowski kick failed)", 6},
+		{"TD", "J.Edelman 12 yd. pass from T.Brady (T.Brady-J.Edelman pass)", 8},
+		{"TD", "J.White 2 yd. run (pass failed)", 6},
+		{"FG", "S.Gostkowski 40 yd. Field Goal", 3},
+		{"SAF", "T.Brady Sacked in End Zone for Safety", 2},
+		{"XP", "Unknown", 0},
+	}
+
+	for _, test := range tests {
+		if points := GetScoringPlayPoints(test.scoringType, test.description); points != test.expected {
+			t.Errorf("%v %v: got %v, expected %v", test.scoringType, test.description, points, test.expected)
+		}
+	}
+}
+
+func TestNewTeamLinescore(t *testing.T) {
+	linescore := NewTeamLinescore("2018090900", "NE", map[int]int{1: 7, 2: 10, 4: 3, 5: 6})
+
+	if !reflect.DeepEqual(linescore.Quarters, []int{7, 10, 0, 3}) || linescore.Overtime != 6 || linescore.Total != 26 {
+		t.Errorf("got quarters %v, overtime %v and total %v, expected 7, 10, 0, 3, 6 and 26", linescore.Quarters,
+			linescore.Overtime, linescore.Total)
+	}
+}
+
+func TestNewGameDetail(t *testing.T) {
+	game := NewGame("2018090900", time.Date(2018, time.September, 9, 0, 0, 0, 0, time.UTC), "NE", "HOU", 17, 10)
+	linescores := []TeamLinescore{
+		NewTeamLinescore(game.GameKey, "HOU", map[int]int{2: 7, 3: 3}),
+		NewTeamLinescore(game.GameKey, "NE", map[int]int{1: 7, 2: 7, 4: 3}),
+	}
+	// Out of order, as the scoring summary is keyed by play id
+	scoringPlays := []ScoringPlay{
+		{PlayId: 900, TeamAbbr: "NE", Points: 3},
+		{PlayId: 100, TeamAbbr: "NE", Points: 7},
+		{PlayId: 300, TeamAbbr: "NE", Points: 7},
+		{PlayId: 500, TeamAbbr: "HOU", Points: 7},
+		{PlayId: 700, TeamAbbr: "HOU", Points: 3},
+	}
+
+	detail := NewGameDetail(game, linescores, scoringPlays)
+
+	if detail.Linescores[0].TeamAbbr != "NE" {
+		t.Errorf("got %v's linescore first, expected the home team's", detail.Linescores[0].TeamAbbr)
+	}
+
+	var scores [][2]int
+	for _, scoringPlay := range detail.ScoringPlays {
+		scores = append(scores, [2]int{scoringPlay.HomeScore, scoringPlay.AwayScore})
+	}
+	expectedScores := [][2]int{{7, 0}, {14, 0}, {14, 7}, {14, 10}, {17, 10}}
+	if !reflect.DeepEqual(scores, expectedScores) {
+		t.Errorf("got scores %v, expected %v", scores, expectedScores)
+	}
+
+	if !reflect.DeepEqual(detail.LargestLeads, map[string]int{"NE": 14, "HOU": 0}) || detail.Margin != 7 {
+		t.Errorf("got largest leads %v and margin %v, expected NE 14, HOU 0 and 7", detail.LargestLeads,
+			detail.Margin)
+	}
+}
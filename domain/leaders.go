@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A player's place on a leaderboard for a stat
+type Leader struct {
+	Rank int `json:"rank"`
+	PlayerId int `json:"playerId"`
+	Name string `json:"name"`
+	TeamAbbr string `json:"teamAbbr"`
+	Position string `json:"position"`
+	Games int `json:"games"`
+	Value float64 `json:"value"`
+}
+
+// The options for ranking players on a leaderboard
+type LeaderboardOptions struct {
+	// A stat key such as "rushing.yds", a metric such as "metrics.passerRating" or "fantasy"
+	Stat string
+	// The ruleset used when the stat is "fantasy"
+	ScoringRuleset ScoringRuleset
+	// Players need at least this many attempts for the stat to be ranked. Only stats with attempts can use it
+	MinAttempts int
+	// Only rank players at this position, such as RB, when it's set
+	Position string
+	// Overridden and roster positions by player id. Other players' positions come from their stats
+	KnownPositions map[int]KnownPosition
+	Limit int
+	IsAscending bool
+}
+
+// Rank the players in the given game stats by the stat in the options. Players tied on the stat are ranked
+// by name
+func NewLeaderboard(playerStats []PlayerStats, options LeaderboardOptions) ([]Leader, error) {
+	getValue, err := getLeaderboardValueFunc(options)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptsStatKey := getAttemptsStatKey(options.Stat)
+	if options.MinAttempts > 0 && len(attemptsStatKey) == 0 {
+		return nil, fmt.Errorf("minAttempts can't be used with %v, which has no attempts", options.Stat)
+	}
+	leaders := []Leader{}
+
+	for _, games := range groupPlayerStatsByPlayer(playerStats) {
+		summary := NewPlayerStatsSummary(games)
+		attempts, _ := summary.StatLine.GetStat(attemptsStatKey)
+
+		if len(attemptsStatKey) > 0 && attempts < float64(options.MinAttempts) {
+			continue
+		}
+
+		position, _ := GetPosition(options.KnownPositions[summary.PlayerId], summary.StatLine)
+		if len(options.Position) > 0 && position != options.Position {
+			continue
+		}
+
+		leaders = append(leaders, Leader {
+			PlayerId: summary.PlayerId,
+			Name: summary.Name,
+			TeamAbbr: summary.TeamAbbr,
+			Position: position,
+			Games: summary.Games,
+			Value: getValue(summary, games),
+		})
+	}
+
+	sort.SliceStable(leaders, func(i, j int) bool {
+		if leaders[i].Value == leaders[j].Value {
+			return leaders[i].Name < leaders[j].Name
+		}
+		if options.IsAscending {
+			return leaders[i].Value < leaders[j].Value
+		}
+		return leaders[i].Value > leaders[j].Value
+	})
+
+	if options.Limit > 0 && len(leaders) > options.Limit {
+		leaders = leaders[:options.Limit]
+	}
+
+	for i := range leaders {
+		leaders[i].Rank = i + 1
+	}
+
+	return leaders, nil
+}
+
+// Get the function that reads the value being ranked from a player's games
+func getLeaderboardValueFunc(options LeaderboardOptions) (func(PlayerStatsSummary, []PlayerStats) float64, error) {
+	stat := strings.ToLower(options.Stat)
+
+	if stat == "fantasy" {
+		return func(summary PlayerStatsSummary, games []PlayerStats) float64 {
+			return options.ScoringRuleset.ScoreGames(games)
+		}, nil
+	}
+
+	if strings.HasPrefix(stat, "metrics.") {
+		metricName := strings.TrimPrefix(stat, "metrics.")
+		if _, ok := (Metrics{}).GetMetric(metricName); !ok {
+			return nil, fmt.Errorf("unknown metric: %v", options.Stat)
+		}
+
+		return func(summary PlayerStatsSummary, games []PlayerStats) float64 {
+			value, _ := NewMetrics(summary.StatLine).GetMetric(metricName)
+			return value
+		}, nil
+	}
+
+	if !IsStatKey(stat) {
+		return nil, fmt.Errorf("unknown stat: %v", options.Stat)
+	}
+
+	return func(summary PlayerStatsSummary, games []PlayerStats) float64 {
+		value, _ := summary.StatLine.GetStat(stat)
+		return value
+	}, nil
+}
+
+// Get the stat key that minimum attempts are checked against for a leaderboard stat
+func getAttemptsStatKey(stat string) string {
+	stat = strings.ToLower(stat)
+
+	if stat == "fantasy" {
+		return ""
+	}
+
+	if strings.HasPrefix(stat, "metrics.") {
+		return getMetricAttemptsStatKey(strings.TrimPrefix(stat, "metrics."))
+	}
+
+	category, ok := GetStatCategory(strings.SplitN(stat, ".", 2)[0])
+	if ok && len(category.AttemptsLabel) > 0 {
+		return category.FeedKey + "." + category.AttemptsLabel
+	}
+	return ""
+}
+
+// Group game stats by player id, keeping each player's games in order
+func groupPlayerStatsByPlayer(playerStats []PlayerStats) map[int][]PlayerStats {
+	gamesByPlayer := make(map[int][]PlayerStats)
+	for _, gameStats := range playerStats {
+		gamesByPlayer[gameStats.PlayerId] = append(gamesByPlayer[gameStats.PlayerId], gameStats)
+	}
+	return gamesByPlayer
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewLeaderboard(t *testing.T) {
+	newGame := func(playerId int, name string, line StatLine) PlayerStats {
+		return PlayerStats{PlayerId: playerId, Name: name, TeamAbbr: "NE", StatLine: line}
+	}
+	playerStats := []PlayerStats{
+		newGame(1, "L.Blount", StatLine{"rushing": {"att": 18, "yds": 70}}),
+		newGame(1, "L.Blount", StatLine{"rushing": {"att": 20, "yds": 60}}),
+		newGame(2, "D.Lewis", StatLine{"rushing": {"att": 12, "yds": 95}}),
+		newGame(3, "J.White", StatLine{"rushing": {"att": 3, "yds": 130}}),
+		// Tied with D.Lewis, as J.White is with L.Blount
+		newGame(4, "B.Bolden", StatLine{"rushing": {"att": 15, "yds": 95}}),
+		newGame(5, "T.Brady", StatLine{"passing": {"att": 35, "yds": 300}, "rushing": {"att": 2, "yds": -2}}),
+	}
+
+	tests := []struct {
+		name string
+		options LeaderboardOptions
+		expectedNames []string
+		expectedValues []float64
+	}{
+		{"most first, with the games added up", LeaderboardOptions{Stat: "rushing.yds"},
+			[]string{"J.White", "L.Blount", "B.Bolden", "D.Lewis", "T.Brady"}, []float64{130, 130, 95, 95, -2}},
+		{"ascending", LeaderboardOptions{Stat: "rushing.yds", IsAscending: true},
+			[]string{"T.Brady", "B.Bolden", "D.Lewis", "J.White", "L.Blount"}, []float64{-2, 95, 95, 130, 130}},
+		{"limited", LeaderboardOptions{Stat: "rushing.yds", Limit: 2},
+			[]string{"J.White", "L.Blount"}, []float64{130, 130}},
+		{"minimum attempts", LeaderboardOptions{Stat: "rushing.yds", MinAttempts: 12},
+			[]string{"L.Blount", "B.Bolden", "D.Lewis"}, []float64{130, 95, 95}},
+		{"metric", LeaderboardOptions{Stat: "metrics.ypc", MinAttempts: 10},
+			[]string{"D.Lewis", "B.Bolden", "L.Blount"}, []float64{7.9, 6.3, 3.4}},
+		{"players without the stat have 0", LeaderboardOptions{Stat: "passing.yds"},
+			[]string{"T.Brady", "B.Bolden", "D.Lewis", "J.White", "L.Blount"}, []float64{300, 0, 0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		leaders, err := NewLeaderboard(playerStats, test.options)
+		if err != nil {
+			t.Errorf("%v: got error %v", test.name, err)
+			continue
+		}
+
+		var names []string
+		var values []float64
+		for i, leader := range leaders {
+			if leader.Rank != i + 1 {
+				t.Errorf("%v: %v is ranked %v, expected %v", test.name, leader.Name, leader.Rank, i + 1)
+			}
+			names = append(names, leader.Name)
+			values = append(values, leader.Value)
+		}
+		if !reflect.DeepEqual(names, test.expectedNames) || !reflect.DeepEqual(values, test.expectedValues) {
+			t.Errorf("%v: got %v with %v, expected %v with %v", test.name, names, values, test.expectedNames,
+				test.expectedValues)
+		}
+	}
+}
+
+func TestNewLeaderboardErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		options LeaderboardOptions
+	}{
+		{"unknown stat", LeaderboardOptions{Stat: "rushing.fumbles"}},
+		{"unknown category", LeaderboardOptions{Stat: "blocking.pancakes"}},
+		{"unknown metric", LeaderboardOptions{Stat: "metrics.qbr"}},
+		{"minimum attempts for a stat without attempts", LeaderboardOptions{Stat: "defense.sk", MinAttempts: 5}},
+		{"minimum attempts for fantasy", LeaderboardOptions{Stat: "fantasy", MinAttempts: 5}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewLeaderboard([]PlayerStats{}, test.options); err == nil {
+			t.Errorf("%v: expected an error", test.name)
+		}
+	}
+}
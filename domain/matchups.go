@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// What a defense allowed to players at a position, per game
+type DefenseMatchup struct {
+	// 1 is the defense that allowed the most fantasy points, the best matchup for the position
+	Rank int `json:"rank"`
+	DefenseAbbr string `json:"defenseAbbr"`
+	Games int `json:"games"`
+	FantasyPoints float64 `json:"fantasyPoints"`
+	FantasyPointsPerGame float64 `json:"fantasyPointsPerGame"`
+	PassingYardsPerGame float64 `json:"passingYardsPerGame"`
+	RushingYardsPerGame float64 `json:"rushingYardsPerGame"`
+	ReceivingYardsPerGame float64 `json:"receivingYardsPerGame"`
+}
+
+// How easy a team's remaining games are for their players at a position
+type RemainingSchedule struct {
+	// 1 is the team whose remaining opponents allowed the most fantasy points to the position
+	Rank int `json:"rank"`
+	TeamAbbr string `json:"teamAbbr"`
+	Opponents []string `json:"opponents"`
+	// The average fantasy points per game the remaining opponents allowed to the position
+	OpponentFantasyPointsPerGame float64 `json:"opponentFantasyPointsPerGame"`
+}
+
+// The defense versus position table for a season
+type MatchupTable struct {
+	Season int `json:"season"`
+	Position string `json:"position"`
+	Scoring string `json:"scoring"`
+	Defenses []DefenseMatchup `json:"defenses"`
+	RemainingSchedules []RemainingSchedule `json:"remainingSchedules"`
+}
+
+// Add up what each defense allowed to players at the position in the season's finished regular season games,
+// and rate each team's remaining games from the schedule by what their opponents allowed. Players without an
+// overridden or roster position get the position their season's stats look like
+func NewMatchupTable(season int, position string, games []Game, remainingGames []Game, playerStats []PlayerStats,
+	knownPositions map[int]KnownPosition, ruleset ScoringRuleset, now time.Time) MatchupTable {
+	table := MatchupTable {
+		Season: season,
+		Position: position,
+		Scoring: ruleset.Name,
+		Defenses: []DefenseMatchup{},
+		RemainingSchedules: []RemainingSchedule{},
+	}
+
+	// The finished regular season games each defense played
+	finishedGames := make(map[string]bool)
+	gamesByDefense := make(map[string]int)
+	var defenseAbbrs []string
+	for _, game := range games {
+		if !game.IsRegularSeason() || !game.IsFinished(now) {
+			continue
+		}
+
+		finishedGames[game.GameKey] = true
+		for _, defenseAbbr := range []string{game.HomeAbbr, game.AwayAbbr} {
+			if gamesByDefense[defenseAbbr] == 0 {
+				defenseAbbrs = append(defenseAbbrs, defenseAbbr)
+			}
+			gamesByDefense[defenseAbbr]++
+		}
+	}
+
+	fantasyPointsByDefense := make(map[string]float64)
+	allowedByDefense := make(map[string]StatLine)
+	for playerId, playerGames := range groupPlayerStatsByPlayer(playerStats) {
+		playerPosition, _ := GetPosition(knownPositions[playerId], NewPlayerStatsSummary(playerGames).StatLine)
+		if playerPosition != position {
+			continue
+		}
+
+		for _, gameStats := range playerGames {
+			if !finishedGames[gameStats.GameKey] {
+				continue
+			}
+			fantasyPointsByDefense[gameStats.Opponent] += ruleset.Score(gameStats.StatLine)
+			allowedByDefense[gameStats.Opponent] = allowedByDefense[gameStats.Opponent].Add(gameStats.StatLine)
+		}
+	}
+
+	fantasyPointsPerGameByDefense := make(map[string]float64)
+	for _, defenseAbbr := range defenseAbbrs {
+		games := float64(gamesByDefense[defenseAbbr])
+		allowed := allowedByDefense[defenseAbbr]
+		fantasyPointsPerGameByDefense[defenseAbbr] = fantasyPointsByDefense[defenseAbbr] / games
+
+		table.Defenses = append(table.Defenses, DefenseMatchup {
+			DefenseAbbr: defenseAbbr,
+			Games: gamesByDefense[defenseAbbr],
+			FantasyPoints: roundToHundredth(fantasyPointsByDefense[defenseAbbr]),
+			FantasyPointsPerGame: roundToHundredth(fantasyPointsPerGameByDefense[defenseAbbr]),
+			PassingYardsPerGame: roundToTenth(allowed.Get("passing", "yds") / games),
+			RushingYardsPerGame: roundToTenth(allowed.Get("rushing", "yds") / games),
+			ReceivingYardsPerGame: roundToTenth(allowed.Get("receiving", "yds") / games),
+		})
+	}
+
+	sort.Slice(table.Defenses, func(i, j int) bool {
+		if table.Defenses[i].FantasyPointsPerGame == table.Defenses[j].FantasyPointsPerGame {
+			return table.Defenses[i].DefenseAbbr < table.Defenses[j].DefenseAbbr
+		}
+		return table.Defenses[i].FantasyPointsPerGame > table.Defenses[j].FantasyPointsPerGame
+	})
+	for i := range table.Defenses {
+		table.Defenses[i].Rank = i + 1
+	}
+
+	table.RemainingSchedules = newRemainingSchedules(remainingGames, fantasyPointsPerGameByDefense)
+	return table
+}
+
+// Rate each team's remaining games by what their opponents allowed per game
+func newRemainingSchedules(remainingGames []Game, fantasyPointsPerGameByDefense map[string]float64) []RemainingSchedule {
+	var teamAbbrs []string
+	opponentsByTeam := make(map[string][]string)
+	for _, game := range remainingGames {
+		for _, teamAbbr := range []string{game.HomeAbbr, game.AwayAbbr} {
+			if _, exists := opponentsByTeam[teamAbbr]; !exists {
+				teamAbbrs = append(teamAbbrs, teamAbbr)
+			}
+			opponentsByTeam[teamAbbr] = append(opponentsByTeam[teamAbbr], game.GetOpponent(teamAbbr))
+		}
+	}
+
+	schedules := []RemainingSchedule{}
+	for _, teamAbbr := range teamAbbrs {
+		total := 0.0
+		for _, opponent := range opponentsByTeam[teamAbbr] {
+			total += fantasyPointsPerGameByDefense[opponent]
+		}
+
+		schedules = append(schedules, RemainingSchedule {
+			TeamAbbr: teamAbbr,
+			Opponents: opponentsByTeam[teamAbbr],
+			OpponentFantasyPointsPerGame: roundToHundredth(total / float64(len(opponentsByTeam[teamAbbr]))),
+		})
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].OpponentFantasyPointsPerGame > schedules[j].OpponentFantasyPointsPerGame
+	})
+	for i := range schedules {
+		schedules[i].Rank = i + 1
+	}
+	return schedules
+}
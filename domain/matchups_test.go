@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewMatchupTable(t *testing.T) {
+	now := time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)
+	games := []Game{newTestGame(2018, 1, "NE", "MIA"), newTestGame(2018, 2, "BUF", "NYJ")}
+	remainingGames := []Game{newTestGame(2018, 5, "NYJ", "NE"), newTestGame(2018, 5, "MIA", "BUF")}
+	newGameStats := func(playerId int, teamAbbr string, game Game, line StatLine) PlayerStats {
+		stats := PlayerStats{PlayerId: playerId, TeamAbbr: teamAbbr, GameDate: game.GameDate, StatLine: line}
+		stats.SetGame(game)
+		return stats
+	}
+	playerStats := []PlayerStats{
+		newGameStats(1, "NE", games[0], StatLine{"rushing": {"att": 20, "yds": 100}}),
+		newGameStats(2, "BUF", games[1], StatLine{"rushing": {"att": 10, "yds": 50}}),
+		// A quarterback against MIA doesn't count for running backs
+		newGameStats(3, "NE", games[0], StatLine{"passing": {"att": 30, "yds": 300}}),
+		// Neither does a running back overridden to a fullback
+		newGameStats(4, "NE", games[0], StatLine{"rushing": {"att": 5, "yds": 200}}),
+	}
+	knownPositions := map[int]KnownPosition{4: {Override: "FB"}}
+	ruleset, _ := GetBuiltInScoringRuleset("standard")
+
+	table := NewMatchupTable(2018, PositionRunningBack, games, remainingGames, playerStats, knownPositions, ruleset, now)
+
+	var defenseAbbrs []string
+	for _, defense := range table.Defenses {
+		defenseAbbrs = append(defenseAbbrs, defense.DefenseAbbr)
+	}
+	if !reflect.DeepEqual(defenseAbbrs, []string{"MIA", "NYJ", "BUF", "NE"}) {
+		t.Errorf("got defenses %v, expected MIA, NYJ, BUF then NE", defenseAbbrs)
+	}
+	if mia := table.Defenses[0]; mia.Rank != 1 || mia.FantasyPointsPerGame != 10 || mia.RushingYardsPerGame != 100 ||
+		mia.PassingYardsPerGame != 0 {
+		t.Errorf("got %+v for MIA", mia)
+	}
+
+	expectedSchedules := []RemainingSchedule{
+		{Rank: 1, TeamAbbr: "BUF", Opponents: []string{"MIA"}, OpponentFantasyPointsPerGame: 10},
+		{Rank: 2, TeamAbbr: "NE", Opponents: []string{"NYJ"}, OpponentFantasyPointsPerGame: 5},
+		{Rank: 3, TeamAbbr: "NYJ", Opponents: []string{"NE"}, OpponentFantasyPointsPerGame: 0},
+		{Rank: 4, TeamAbbr: "MIA", Opponents: []string{"BUF"}, OpponentFantasyPointsPerGame: 0},
+	}
+	if !reflect.DeepEqual(table.RemainingSchedules, expectedSchedules) {
+		t.Errorf("got remaining schedules %+v, expected %+v", table.RemainingSchedules, expectedSchedules)
+	}
+}
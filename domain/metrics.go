@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// Derived efficiency metrics for a stat line
+type Metrics struct {
+	PasserRating float64 `json:"passerRating"`
+	CompletionPct float64 `json:"cmpPct"`
+	YardsPerAttempt float64 `json:"ypa"`
+	TouchdownPct float64 `json:"tdPct"`
+	InterceptionPct float64 `json:"intPct"`
+	YardsPerCarry float64 `json:"ypc"`
+	YardsPerReception float64 `json:"ypr"`
+	PassingTwoPointPct float64 `json:"passTwoPtPct"`
+	RushingTwoPointPct float64 `json:"rushTwoPtPct"`
+	ReceivingTwoPointPct float64 `json:"recTwoPtPct"`
+	FieldGoalPct float64 `json:"fgPct"`
+	ExtraPointPct float64 `json:"xpPct"`
+	KickReturnAverage float64 `json:"kickRetAvg"`
+	PuntReturnAverage float64 `json:"puntRetAvg"`
+	PuntAverage float64 `json:"puntAvg"`
+	InsideTwentyPct float64 `json:"i20Pct"`
+}
+
+// Calculate the derived metrics for the given stat line
+func NewMetrics(line StatLine) Metrics {
+	return Metrics {
+		PasserRating: GetPasserRating(line),
+		CompletionPct: getPercentage(line.Get("passing", "cmp"), line.Get("passing", "att")),
+		YardsPerAttempt: getRate(line.Get("passing", "yds"), line.Get("passing", "att")),
+		TouchdownPct: getPercentage(line.Get("passing", "tds"), line.Get("passing", "att")),
+		InterceptionPct: getPercentage(line.Get("passing", "ints"), line.Get("passing", "att")),
+		YardsPerCarry: getRate(line.Get("rushing", "yds"), line.Get("rushing", "att")),
+		YardsPerReception: getRate(line.Get("receiving", "yds"), line.Get("receiving", "rec")),
+		PassingTwoPointPct: getPercentage(line.Get("passing", "twoptm"), line.Get("passing", "twopta")),
+		RushingTwoPointPct: getPercentage(line.Get("rushing", "twoptm"), line.Get("rushing", "twopta")),
+		ReceivingTwoPointPct: getPercentage(line.Get("receiving", "twoptm"), line.Get("receiving", "twopta")),
+		FieldGoalPct: getPercentage(line.Get("kicking", "fgm"), line.Get("kicking", "fga")),
+		ExtraPointPct: getPercentage(line.Get("kicking", "xpmade"), line.Get("kicking", "xpa")),
+		KickReturnAverage: getRate(line.Get("kickret", "yds"), line.Get("kickret", "ret")),
+		PuntReturnAverage: getRate(line.Get("puntret", "yds"), line.Get("puntret", "ret")),
+		PuntAverage: getRate(line.Get("punting", "yds"), line.Get("punting", "pts")),
+		InsideTwentyPct: getPercentage(line.Get("punting", "i20"), line.Get("punting", "pts")),
+	}
+}
+
+// Calculate the NFL passer rating for the passing stats in the line
+func GetPasserRating(line StatLine) float64 {
+	att := line.Get("passing", "att")
+	if att == 0 {
+		return 0
+	}
+
+	// Each component is capped between 0 and 2.375 per the NFL formula
+	completions := clampRatingComponent((line.Get("passing", "cmp") / att - 0.3) * 5)
+	yards := clampRatingComponent((line.Get("passing", "yds") / att - 3) * 0.25)
+	touchdowns := clampRatingComponent(line.Get("passing", "tds") / att * 20)
+	interceptions := clampRatingComponent(2.375 - line.Get("passing", "ints") / att * 25)
+
+	rating := (completions + yards + touchdowns + interceptions) / 6 * 100
+	return roundToTenth(rating)
+}
+
+// Limit a passer rating component to the range allowed by the formula
+func clampRatingComponent(value float64) float64 {
+	return math.Max(0, math.Min(2.375, value))
+}
+
+// Get the rate of a stat per attempt, rounded to a tenth
+func getRate(value float64, attempts float64) float64 {
+	if attempts == 0 {
+		return 0
+	}
+	return roundToTenth(value / attempts)
+}
+
+// Get the percentage of successes per attempt, rounded to a tenth
+func getPercentage(successes float64, attempts float64) float64 {
+	if attempts == 0 {
+		return 0
+	}
+	return roundToTenth(successes / attempts * 100)
+}
+
+func roundToTenth(value float64) float64 {
+	return math.Round(value * 10) / 10
+}
+
+func roundToHundredth(value float64) float64 {
+	return math.Round(value * 100) / 100
+}
+
+// Get the value of a metric given its json name, such as "passerRating"
+func (metrics Metrics) GetMetric(name string) (float64, bool) {
+	values := map[string]float64 {
+		"passerrating": metrics.PasserRating,
+		"cmppct": metrics.CompletionPct,
+		"ypa": metrics.YardsPerAttempt,
+		"tdpct": metrics.TouchdownPct,
+		"intpct": metrics.InterceptionPct,
+		"ypc": metrics.YardsPerCarry,
+		"ypr": metrics.YardsPerReception,
+		"passtwoptpct": metrics.PassingTwoPointPct,
+		"rushtwoptpct": metrics.RushingTwoPointPct,
+		"rectwoptpct": metrics.ReceivingTwoPointPct,
+		"fgpct": metrics.FieldGoalPct,
+		"xppct": metrics.ExtraPointPct,
+		"kickretavg": metrics.KickReturnAverage,
+		"puntretavg": metrics.PuntReturnAverage,
+		"puntavg": metrics.PuntAverage,
+		"i20pct": metrics.InsideTwentyPct,
+	}
+
+	value, ok := values[strings.ToLower(name)]
+	return value, ok
+}
+
+// Get the stat key for the attempts a metric is calculated over
+func getMetricAttemptsStatKey(name string) string {
+	switch strings.ToLower(name) {
+	case "ypc":
+		return "rushing.att"
+	case "rushtwoptpct":
+		return "rushing.twopta"
+	case "ypr":
+		return "receiving.rec"
+	case "rectwoptpct":
+		return "receiving.twopta"
+	case "passtwoptpct":
+		return "passing.twopta"
+	case "fgpct":
+		return "kicking.fga"
+	case "xppct":
+		return "kicking.xpa"
+	case "kickretavg":
+		return "kickret.ret"
+	case "puntretavg":
+		return "puntret.ret"
+	case "puntavg", "i20pct":
+		return "punting.pts"
+	}
+	return "passing.att"
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestGetPasserRating(t *testing.T) {
+	tests := []struct {
+		name string
+		passing CategoryStats
+		expected float64
+	}{
+		{"no attempts", CategoryStats{}, 0},
+		{"perfect", CategoryStats{"att": 20, "cmp": 20, "yds": 400, "tds": 5}, 158.3},
+		{"worst", CategoryStats{"att": 10, "ints": 3}, 0},
+		{"incompletions only", CategoryStats{"att": 10}, 39.6},
+		// Tom Brady's 2007 season
+		{"season", CategoryStats{"att": 578, "cmp": 398, "yds": 4806, "tds": 50, "ints": 8}, 117.2},
+	}
+
+	for _, test := range tests {
+		if rating := GetPasserRating(StatLine{"passing": test.passing}); rating != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, rating, test.expected)
+		}
+	}
+}
+
+func TestGetMetric(t *testing.T) {
+	metrics := NewMetrics(StatLine{
+		"passing": {"att": 30, "cmp": 20, "yds": 245, "tds": 2, "ints": 1},
+		"rushing": {"att": 7, "yds": 30},
+		"kicking": {"fga": 3, "fgm": 2},
+	})
+
+	tests := []struct {
+		name string
+		expected float64
+		isMetric bool
+	}{
+		{"cmpPct", 66.7, true},
+		{"YPA", 8.2, true},
+		{"intPct", 3.3, true},
+		{"ypc", 4.3, true},
+		{"ypr", 0, true},
+		{"fgPct", 66.7, true},
+		{"yardsPerCarry", 0, false},
+	}
+
+	for _, test := range tests {
+		value, ok := metrics.GetMetric(test.name)
+		if value != test.expected || ok != test.isMetric {
+			t.Errorf("%v: got %v %v, expected %v %v", test.name, value, ok, test.expected, test.isMetric)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A play in a drive, from the plays section of a drive in the feed
+type Play struct {
+	GameKey string `json:"gameKey"`
+	PlayId int `json:"playId"`
+	DriveNum int `json:"driveNum"`
+	// The team with the ball
+	TeamAbbr string `json:"teamAbbr"`
+	DefenseAbbr string `json:"defenseAbbr"`
+	Quarter int `json:"quarter"`
+	Down int `json:"down"`
+	YardsToGo int `json:"yardsToGo"`
+	Time string `json:"time"`
+	YardLine string `json:"yardLine"`
+	// Yards from the offense's own goal line, so 80 and over is the red zone
+	FieldPosition int `json:"fieldPosition"`
+	Yards int `json:"yards"`
+	Description string `json:"description"`
+	// Notes such as TD, XP, INT or FUMBLE
+	Note string `json:"note"`
+	Players []PlayPlayer `json:"players"`
+}
+
+// A player's part in a play, such as the passer or the receiver
+type PlayPlayer struct {
+	// The feed's id for the player
+	PlayerKey string `json:"-"`
+	PlayerId int `json:"playerId"`
+	Name string `json:"name"`
+	TeamAbbr string `json:"teamAbbr"`
+	// The feed's id for the stat the player recorded on the play
+	StatId int `json:"statId"`
+	Yards int `json:"yards"`
+}
+
+// The feed's stat ids for the offensive players in a play
+const (
+	statIdRush = 10
+	statIdRushTd = 11
+	statIdIncompletePass = 14
+	statIdCompletePass = 15
+	statIdCompletePassTd = 16
+	statIdInterception = 19
+	statIdSack = 20
+	statIdReception = 21
+	statIdReceptionTd = 22
+	statIdTarget = 115
+)
+
+var (
+	passerStatIds = []int{statIdIncompletePass, statIdCompletePass, statIdCompletePassTd, statIdInterception, statIdSack}
+	rusherStatIds = []int{statIdRush, statIdRushTd}
+	receiverStatIds = []int{statIdReception, statIdReceptionTd, statIdTarget}
+)
+
+// The parts an offensive player can have in a play
+const (
+	PlayRolePasser = "passer"
+	PlayRoleRusher = "rusher"
+	PlayRoleReceiver = "receiver"
+)
+
+// Filters for searching plays. Empty values aren't filtered on
+type PlaySearchOptions struct {
+	Text string
+	PlayerId int
+	TeamAbbr string
+	Season int
+	IsRedZone bool
+	Limit int
+}
+
+// Is the play inside the opponent's 20 yard line?
+func (play Play) IsRedZone() bool {
+	return play.FieldPosition >= 80
+}
+
+// Is the play in the last two minutes of a half?
+func (play Play) IsTwoMinuteDrill() bool {
+	return (play.Quarter == 2 || play.Quarter == 4) && ParseClockSeconds(play.Time) <= 120
+}
+
+// Is the play from scrimmage with a down, rather than a kickoff, try or timeout?
+func (play Play) IsScrimmage() bool {
+	return play.Down >= 1 && play.Down <= 4 && len(play.TeamAbbr) > 0
+}
+
+// Get the half the play is in, with overtime as the third
+func (play Play) GetHalf() int {
+	if play.Quarter > 4 {
+		return 3
+	}
+	return (play.Quarter + 1) / 2
+}
+
+// Get each offensive player in the play, such as the passer and receiver. Players have an entry
+// for every stat they recorded, so only the first entry for each player is included
+func (play Play) GetOffensivePlayers() []PlayPlayer {
+	var offensivePlayers []PlayPlayer
+	isAdded := make(map[string]bool)
+
+	for _, player := range play.Players {
+		if len(player.GetRole()) == 0 || player.TeamAbbr != play.TeamAbbr || isAdded[player.PlayerKey] {
+			continue
+		}
+		isAdded[player.PlayerKey] = true
+		offensivePlayers = append(offensivePlayers, player)
+	}
+	return offensivePlayers
+}
+
+// Get the offensive part the player had in the play from their stat id, or an empty string if they had none
+func (player PlayPlayer) GetRole() string {
+	switch {
+	case containsStatId(passerStatIds, player.StatId):
+		return PlayRolePasser
+	case containsStatId(rusherStatIds, player.StatId):
+		return PlayRoleRusher
+	case containsStatId(receiverStatIds, player.StatId):
+		return PlayRoleReceiver
+	}
+	return ""
+}
+
+func containsStatId(statIds []int, statId int) bool {
+	for _, id := range statIds {
+		if id == statId {
+			return true
+		}
+	}
+	return false
+}
+
+// Get how far a yard line such as "NE 25" is from the given team's own goal line.
+// The 50 has no team in the feed
+func GetFieldPosition(yardLine string, teamAbbr string) (int, bool) {
+	parts := strings.Fields(yardLine)
+	if len(parts) == 0 {
+		return 0, false
+	}
+
+	yards, err := strconv.Atoi(parts[len(parts) - 1])
+	if err != nil {
+		return 0, false
+	}
+
+	if len(parts) == 1 || parts[0] == teamAbbr {
+		return yards, true
+	}
+	return 100 - yards, true
+}
+
+// Get a key for a play that's unique across games
+func GetPlayKey(gameKey string, playId int) string {
+	return fmt.Sprintf("%v-%v", gameKey, playId)
+}
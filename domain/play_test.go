@@ -0,0 +1,45 @@
+package domain
+
+import "testing"
+
+func TestGetFieldPosition(t *testing.T) {
+	tests := []struct {
+		yardLine string
+		teamAbbr string
+		expected int
+		ok bool
+	}{
+		{"NE 25", "NE", 25, true},
+		{"MIA 25", "NE", 75, true},
+		{"MIA 1", "NE", 99, true},
+		// The 50 has no team
+		{"50", "NE", 50, true},
+		{"", "NE", 0, false},
+		{"NE", "NE", 0, false},
+	}
+
+	for _, test := range tests {
+		fieldPosition, ok := GetFieldPosition(test.yardLine, test.teamAbbr)
+		if fieldPosition != test.expected || ok != test.ok {
+			t.Errorf("%v for %v: got %v, %v, expected %v, %v", test.yardLine, test.teamAbbr, fieldPosition, ok,
+				test.expected, test.ok)
+		}
+	}
+}
+
+func TestIsRedZone(t *testing.T) {
+	tests := []struct {
+		fieldPosition int
+		expected bool
+	}{
+		{79, false},
+		{80, true},
+		{99, true},
+	}
+
+	for _, test := range tests {
+		if isRedZone := (Play{FieldPosition: test.fieldPosition}).IsRedZone(); isRedZone != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.fieldPosition, isRedZone, test.expected)
+		}
+	}
+}
@@ -2,6 +2,12 @@ package domain
 
 type Player struct {
 	Id int
+	// The name as the feed writes it, such as "T.Brady"
 	Name string
+	// The name from an imported roster, or "" when the player isn't on one
+	FullName string
 	Team string
+	Position string
+	// Where the position came from: override, roster or inferred
+	PositionSource string
 }
@@ -0,0 +1,444 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+
+// Common games are only used for wild card ties when each team has played at least this many
+const minWildCardCommonGames = 4
+
+// A team's place in the playoff picture
+type PlayoffSeed struct {
+	Seed int `json:"seed,omitempty"`
+	IsDivisionWinner bool `json:"isDivisionWinner"`
+	// The tiebreaker that put the team ahead of the teams tied with them, if there was a tie
+	Tiebreaker string `json:"tiebreaker,omitempty"`
+	TeamStanding
+}
+
+// A conference's playoff seeds, and the rest of its teams in the order they'd get in
+type ConferencePlayoffPicture struct {
+	Conference string `json:"conference"`
+	Seeds []PlayoffSeed `json:"seeds"`
+	OutOfPlayoffs []PlayoffSeed `json:"outOfPlayoffs"`
+}
+
+// The regular season games and standings used to break ties
+type seasonResults struct {
+	standings map[string]TeamStanding
+	gamesByTeam map[string][]Game
+	// Each team's place in their division, from 0 for first
+	divisionRanks map[string]int
+}
+
+// A step in the tiebreaking procedure. It gets a value for each tied team, where higher is better,
+// or nil when the step doesn't apply to the tied teams
+type tiebreakStep struct {
+	name string
+	getValues func(results seasonResults, tied []string) map[string]float64
+}
+
+// Tiebreaking steps for teams in the same division
+var divisionTiebreakSteps = []tiebreakStep {
+	{"head-to-head", getHeadToHeadValues},
+	{"division record", getDivisionRecordValues},
+	{"common games", getCommonGamesValues(0)},
+	{"conference record", getConferenceRecordValues},
+	{"strength of victory", getStrengthOfVictoryValues},
+	{"strength of schedule", getStrengthOfScheduleValues},
+}
+
+// Tiebreaking steps for teams in different divisions
+var wildCardTiebreakSteps = []tiebreakStep {
+	{"head-to-head", getHeadToHeadSweepValues},
+	{"conference record", getConferenceRecordValues},
+	{"common games", getCommonGamesValues(minWildCardCommonGames)},
+	{"strength of victory", getStrengthOfVictoryValues},
+	{"strength of schedule", getStrengthOfScheduleValues},
+}
+
+// Get the number of teams each conference sends to the playoffs: 6 until 2020 added a third wild card
+func GetPlayoffSeeds(season int) int {
+	if season >= 2020 {
+		return 7
+	}
+	return 6
+}
+
+// Work out each conference's playoff seeds from the season's regular season games finished by now, using the
+// NFL's tiebreakers.
+// Division winners take the top seeds and the best of the rest take the wild cards. Ties still left after
+// strength of schedule go alphabetically in place of a coin toss
+func NewPlayoffPicture(season int, games []Game, now time.Time) []ConferencePlayoffPicture {
+	playoffSeeds := GetPlayoffSeeds(season)
+	results := newSeasonResults(games, now)
+
+	pictures := []ConferencePlayoffPicture{}
+	for _, conference := range []string{"AFC", "NFC"} {
+		picture := ConferencePlayoffPicture {
+			Conference: conference,
+			Seeds: []PlayoffSeed{},
+			OutOfPlayoffs: []PlayoffSeed{},
+		}
+
+		// Rank each division to find its winner
+		var divisionWinners []string
+		var others []string
+		for _, division := range results.getDivisions(conference) {
+			ranked := results.rankTeams(division, false)
+			for rank, seed := range ranked {
+				results.divisionRanks[seed.Abbr] = rank
+			}
+
+			divisionWinners = append(divisionWinners, ranked[0].Abbr)
+			for _, seed := range ranked[1:] {
+				others = append(others, seed.Abbr)
+			}
+		}
+
+		for _, seed := range results.rankTeams(divisionWinners, true) {
+			seed.IsDivisionWinner = true
+			seed.Seed = len(picture.Seeds) + 1
+			picture.Seeds = append(picture.Seeds, seed)
+		}
+		for _, seed := range results.rankTeams(others, true) {
+			if len(picture.Seeds) < playoffSeeds {
+				seed.Seed = len(picture.Seeds) + 1
+				picture.Seeds = append(picture.Seeds, seed)
+			} else {
+				picture.OutOfPlayoffs = append(picture.OutOfPlayoffs, seed)
+			}
+		}
+
+		pictures = append(pictures, picture)
+	}
+
+	return pictures
+}
+
+// Get the standings and each team's games from the regular season games finished by now
+func newSeasonResults(games []Game, now time.Time) seasonResults {
+	results := seasonResults {
+		standings: newTeamStandings(games, now),
+		gamesByTeam: make(map[string][]Game),
+		divisionRanks: make(map[string]int),
+	}
+	for _, game := range games {
+		if isStandingsGame(game, now) {
+			results.gamesByTeam[game.HomeAbbr] = append(results.gamesByTeam[game.HomeAbbr], game)
+			results.gamesByTeam[game.AwayAbbr] = append(results.gamesByTeam[game.AwayAbbr], game)
+		}
+	}
+	return results
+}
+
+// Get the teams with standings in each division of the conference
+func (results seasonResults) getDivisions(conference string) [][]string {
+	var divisions [][]string
+	divisionIndexes := make(map[string]int)
+
+	for _, teamInfo := range teamInfos {
+		if teamInfo.Conference != conference {
+			continue
+		}
+		if _, ok := results.standings[teamInfo.Abbr]; !ok {
+			continue
+		}
+
+		if _, exists := divisionIndexes[teamInfo.Division]; !exists {
+			divisionIndexes[teamInfo.Division] = len(divisions)
+			divisions = append(divisions, []string{})
+		}
+		index := divisionIndexes[teamInfo.Division]
+		divisions[index] = append(divisions[index], teamInfo.Abbr)
+	}
+	return divisions
+}
+
+// Rank the teams by win percentage, breaking ties with the division or wild card steps.
+// Once a team is placed, the rest of the tied teams start the tiebreakers again
+func (results seasonResults) rankTeams(teams []string, isWildCard bool) []PlayoffSeed {
+	remaining := append([]string{}, teams...)
+	sort.Strings(remaining)
+	var ranked []PlayoffSeed
+
+	for len(remaining) > 0 {
+		tied := results.getBestWinPctTeams(remaining)
+		best := tied[0]
+		tiebreaker := ""
+		if len(tied) > 1 {
+			best, tiebreaker = results.breakTie(tied, isWildCard)
+		}
+
+		ranked = append(ranked, PlayoffSeed {
+			Tiebreaker: tiebreaker,
+			TeamStanding: results.standings[best],
+		})
+		remaining = removeTeam(remaining, best)
+	}
+	return ranked
+}
+
+// Get the team that wins a tie, and the step that decided it
+func (results seasonResults) breakTie(tied []string, isWildCard bool) (string, string) {
+	remaining := tied
+	if isWildCard {
+		remaining = results.getBestInEachDivision(remaining)
+		if len(remaining) == 1 {
+			return remaining[0], "division tiebreaker"
+		}
+	}
+
+	for len(remaining) > 1 {
+		// Teams left from the same division use the division steps
+		steps := divisionTiebreakSteps
+		if isWildCard && !results.isOneDivision(remaining) {
+			steps = wildCardTiebreakSteps
+		}
+
+		isNarrowed := false
+		for _, step := range steps {
+			values := step.getValues(results, remaining)
+			if values == nil {
+				continue
+			}
+
+			best := getBestValueTeams(remaining, values)
+			if len(best) == 1 {
+				return best[0], step.name
+			}
+			if len(best) < len(remaining) {
+				// Start the steps again with the teams still tied
+				remaining = best
+				isNarrowed = true
+				break
+			}
+		}
+
+		if !isNarrowed {
+			return remaining[0], "coin toss"
+		}
+	}
+	return remaining[0], ""
+}
+
+// Get the tied teams with the best win percentage
+func (results seasonResults) getBestWinPctTeams(teams []string) []string {
+	values := make(map[string]float64)
+	for _, teamAbbr := range teams {
+		values[teamAbbr] = results.standings[teamAbbr].WinPct
+	}
+	return getBestValueTeams(teams, values)
+}
+
+// Keep only the highest ranked team in their division out of the tied teams
+func (results seasonResults) getBestInEachDivision(tied []string) []string {
+	bestByDivision := make(map[string]string)
+	for _, teamAbbr := range tied {
+		division := results.standings[teamAbbr].Division
+		best, exists := bestByDivision[division]
+		if !exists || results.divisionRanks[teamAbbr] < results.divisionRanks[best] {
+			bestByDivision[division] = teamAbbr
+		}
+	}
+
+	var best []string
+	for _, teamAbbr := range tied {
+		if bestByDivision[results.standings[teamAbbr].Division] == teamAbbr {
+			best = append(best, teamAbbr)
+		}
+	}
+	return best
+}
+
+// Are the teams all in the same division?
+func (results seasonResults) isOneDivision(teams []string) bool {
+	for _, teamAbbr := range teams[1:] {
+		if !IsDivisionGame(teams[0], teamAbbr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Get the team's record in games against the given opponents
+func (results seasonResults) getRecordAgainst(teamAbbr string, opponents []string) Record {
+	record := Record{}
+	for _, game := range results.gamesByTeam[teamAbbr] {
+		if containsTeam(opponents, game.GetOpponent(teamAbbr)) {
+			record = record.add(game.GetResult(teamAbbr))
+		}
+	}
+	return record
+}
+
+// Get the combined record of the given teams, counting a team once for each time it's in the list
+func (results seasonResults) getCombinedRecord(teams []string) Record {
+	combined := Record{}
+	for _, teamAbbr := range teams {
+		record := results.standings[teamAbbr].Record
+		combined.Wins += record.Wins
+		combined.Losses += record.Losses
+		combined.Ties += record.Ties
+	}
+	return combined
+}
+
+// Win percentage in games between the tied teams
+func getHeadToHeadValues(results seasonResults, tied []string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		record := results.getRecordAgainst(teamAbbr, tied)
+		if record.getGames() == 0 {
+			return nil
+		}
+		values[teamAbbr] = record.GetWinPct()
+	}
+	return values
+}
+
+// Head-to-head between teams from different divisions. With two teams it's their games against each other.
+// With more, it only counts when one team beat all the others or lost to all of them
+func getHeadToHeadSweepValues(results seasonResults, tied []string) map[string]float64 {
+	if len(tied) == 2 {
+		return getHeadToHeadValues(results, tied)
+	}
+
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		hasBeatenAll := true
+		hasLostToAll := true
+		for _, otherAbbr := range tied {
+			if otherAbbr == teamAbbr {
+				continue
+			}
+			record := results.getRecordAgainst(teamAbbr, []string{otherAbbr})
+			hasBeatenAll = hasBeatenAll && record.Wins > 0 && record.Losses == 0 && record.Ties == 0
+			hasLostToAll = hasLostToAll && record.Losses > 0 && record.Wins == 0 && record.Ties == 0
+		}
+
+		switch {
+		case hasBeatenAll:
+			values[teamAbbr] = 1
+		case hasLostToAll:
+			values[teamAbbr] = -1
+		default:
+			values[teamAbbr] = 0
+		}
+	}
+	return values
+}
+
+func getDivisionRecordValues(results seasonResults, tied []string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		values[teamAbbr] = results.standings[teamAbbr].DivisionRecord.GetWinPct()
+	}
+	return values
+}
+
+func getConferenceRecordValues(results seasonResults, tied []string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		values[teamAbbr] = results.standings[teamAbbr].ConferenceRecord.GetWinPct()
+	}
+	return values
+}
+
+// Win percentage against opponents every tied team has played. The step is skipped when a team
+// has played fewer than the minimum number of common games
+func getCommonGamesValues(minGames int) func(seasonResults, []string) map[string]float64 {
+	return func(results seasonResults, tied []string) map[string]float64 {
+		var commonOpponents []string
+		for _, game := range results.gamesByTeam[tied[0]] {
+			opponent := game.GetOpponent(tied[0])
+			if containsTeam(commonOpponents, opponent) || containsTeam(tied, opponent) {
+				continue
+			}
+
+			isCommon := true
+			for _, teamAbbr := range tied[1:] {
+				isCommon = isCommon && results.getRecordAgainst(teamAbbr, []string{opponent}).getGames() > 0
+			}
+			if isCommon {
+				commonOpponents = append(commonOpponents, opponent)
+			}
+		}
+
+		values := make(map[string]float64)
+		for _, teamAbbr := range tied {
+			record := results.getRecordAgainst(teamAbbr, commonOpponents)
+			if record.getGames() == 0 || record.getGames() < minGames {
+				return nil
+			}
+			values[teamAbbr] = record.GetWinPct()
+		}
+		return values
+	}
+}
+
+// The combined win percentage of the teams each tied team beat
+func getStrengthOfVictoryValues(results seasonResults, tied []string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		var defeated []string
+		for _, game := range results.gamesByTeam[teamAbbr] {
+			if game.GetResult(teamAbbr) == "W" {
+				defeated = append(defeated, game.GetOpponent(teamAbbr))
+			}
+		}
+		values[teamAbbr] = results.getCombinedRecord(defeated).GetWinPct()
+	}
+	return values
+}
+
+// The combined win percentage of every opponent each tied team played
+func getStrengthOfScheduleValues(results seasonResults, tied []string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, teamAbbr := range tied {
+		var opponents []string
+		for _, game := range results.gamesByTeam[teamAbbr] {
+			opponents = append(opponents, game.GetOpponent(teamAbbr))
+		}
+		values[teamAbbr] = results.getCombinedRecord(opponents).GetWinPct()
+	}
+	return values
+}
+
+func (record Record) getGames() int {
+	return record.Wins + record.Losses + record.Ties
+}
+
+// Get the teams with the highest value, in the order they were given
+func getBestValueTeams(teams []string, values map[string]float64) []string {
+	var best []string
+	for _, teamAbbr := range teams {
+		if len(best) == 0 || values[teamAbbr] > values[best[0]] {
+			best = []string{teamAbbr}
+		} else if values[teamAbbr] == values[best[0]] {
+			best = append(best, teamAbbr)
+		}
+	}
+	return best
+}
+
+func containsTeam(teams []string, teamAbbr string) bool {
+	for _, team := range teams {
+		if team == teamAbbr {
+			return true
+		}
+	}
+	return false
+}
+
+func removeTeam(teams []string, teamAbbr string) []string {
+	var remaining []string
+	for _, team := range teams {
+		if team != teamAbbr {
+			remaining = append(remaining, team)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// After the last season the playoff tests use, so all of their games are finished
+var afterTestSeasons = time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+// Games from a list of "winner-loser" results, one week each
+func newTestGames(results ...[2]string) []Game {
+	var games []Game
+	for week, result := range results {
+		games = append(games, newTestGame(2018, week + 1, result[0], result[1]))
+	}
+	return games
+}
+
+func TestBreakTie(t *testing.T) {
+	tests := []struct {
+		name string
+		games []Game
+		tied []string
+		isWildCard bool
+		expectedWinner string
+		expectedTiebreaker string
+	}{
+		{
+			name: "division head-to-head",
+			games: newTestGames([2]string{"NE", "MIA"}, [2]string{"KC", "NE"}, [2]string{"MIA", "DEN"}),
+			tied: []string{"MIA", "NE"},
+			expectedWinner: "NE",
+			expectedTiebreaker: "head-to-head",
+		},
+		{
+			name: "division record after a split head-to-head",
+			games: newTestGames([2]string{"NE", "MIA"}, [2]string{"MIA", "NE"}, [2]string{"NE", "BUF"},
+				[2]string{"BUF", "MIA"}, [2]string{"KC", "NE"}, [2]string{"MIA", "KC"}),
+			tied: []string{"MIA", "NE"},
+			expectedWinner: "NE",
+			expectedTiebreaker: "division record",
+		},
+		{
+			name: "division strength of victory",
+			games: newTestGames([2]string{"NE", "KC"}, [2]string{"GB", "NE"}, [2]string{"MIA", "DEN"},
+				[2]string{"CHI", "MIA"}, [2]string{"KC", "HOU"}),
+			tied: []string{"MIA", "NE"},
+			expectedWinner: "NE",
+			expectedTiebreaker: "strength of victory",
+		},
+		{
+			name: "wild card conference record",
+			games: newTestGames([2]string{"NE", "BAL"}, [2]string{"GB", "NE"}, [2]string{"HOU", "GB"},
+				[2]string{"CIN", "HOU"}),
+			tied: []string{"HOU", "NE"},
+			isWildCard: true,
+			expectedWinner: "NE",
+			expectedTiebreaker: "conference record",
+		},
+		{
+			name: "wild card head-to-head sweep",
+			games: newTestGames([2]string{"NE", "HOU"}, [2]string{"NE", "DEN"}, [2]string{"GB", "NE"},
+				[2]string{"CHI", "NE"}, [2]string{"HOU", "DEN"}, [2]string{"HOU", "ATL"}, [2]string{"TB", "HOU"},
+				[2]string{"DEN", "DET"}, [2]string{"DEN", "MIN"}),
+			tied: []string{"DEN", "HOU", "NE"},
+			isWildCard: true,
+			expectedWinner: "NE",
+			expectedTiebreaker: "head-to-head",
+		},
+		{
+			name: "wild card keeps only the best team from a division",
+			games: newTestGames([2]string{"NE", "MIA"}, [2]string{"KC", "NE"}, [2]string{"MIA", "DEN"},
+				[2]string{"CIN", "HOU"}, [2]string{"HOU", "LAC"}),
+			tied: []string{"HOU", "MIA", "NE"},
+			isWildCard: true,
+			expectedWinner: "NE",
+			expectedTiebreaker: "strength of victory",
+		},
+		{
+			name: "coin toss",
+			games: newTestGames([2]string{"NE", "KC"}, [2]string{"MIA", "KC"}),
+			tied: []string{"MIA", "NE"},
+			expectedWinner: "MIA",
+			expectedTiebreaker: "coin toss",
+		},
+	}
+
+	for _, test := range tests {
+		results := newSeasonResults(test.games, afterTestSeasons)
+		// Rank the divisions first, as the wild card tiebreakers use the division ranks
+		for _, conference := range []string{"AFC", "NFC"} {
+			for _, division := range results.getDivisions(conference) {
+				for rank, seed := range results.rankTeams(division, false) {
+					results.divisionRanks[seed.Abbr] = rank
+				}
+			}
+		}
+
+		winner, tiebreaker := results.breakTie(test.tied, test.isWildCard)
+		if winner != test.expectedWinner || tiebreaker != test.expectedTiebreaker {
+			t.Errorf("%v: got %v by %v, expected %v by %v", test.name, winner, tiebreaker,
+				test.expectedWinner, test.expectedTiebreaker)
+		}
+	}
+}
+
+func TestNewPlayoffPictureSeeds(t *testing.T) {
+	tests := []struct {
+		season int
+		expectedSeeds int
+	}{
+		{2018, 6},
+		{2019, 6},
+		{2020, 7},
+		{2022, 7},
+	}
+
+	for _, test := range tests {
+		picture := NewPlayoffPicture(test.season, newRandomSeason(test.season), afterTestSeasons)
+		for _, conference := range picture {
+			if len(conference.Seeds) != test.expectedSeeds {
+				t.Errorf("%v %v has %v seeds, expected %v", test.season, conference.Conference,
+					len(conference.Seeds), test.expectedSeeds)
+			}
+			if len(conference.Seeds) + len(conference.OutOfPlayoffs) != 16 {
+				t.Errorf("%v %v has %v teams, expected 16", test.season, conference.Conference,
+					len(conference.Seeds) + len(conference.OutOfPlayoffs))
+			}
+
+			for i, seed := range conference.Seeds {
+				if seed.Seed != i + 1 || seed.IsDivisionWinner != (i < 4) {
+					t.Errorf("%v %v seed %v is %v with seed %v, division winner %v", test.season,
+						conference.Conference, i + 1, seed.Abbr, seed.Seed, seed.IsDivisionWinner)
+				}
+			}
+		}
+	}
+}
+
+// A season of random results between the current teams
+func newRandomSeason(season int) []Game {
+	random := rand.New(rand.NewSource(int64(season)))
+	var teams []string
+	for _, teamInfo := range teamInfos {
+		if teamInfo.Abbr != "JAC" && teamInfo.Abbr != "SD" && teamInfo.Abbr != "STL" && teamInfo.Abbr != "OAK" {
+			teams = append(teams, teamInfo.Abbr)
+		}
+	}
+
+	var games []Game
+	for week := 1; week <= GetRegularSeasonWeeks(season); week++ {
+		order := random.Perm(len(teams))
+		for i := 0; i + 1 < len(order); i += 2 {
+			games = append(games, newTestGame(season, week, teams[order[i]], teams[order[i + 1]]))
+		}
+	}
+	return games
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Splits that need the player's plays rather than their game totals
+var playSplits = []string{"quarter", "down", "distance", "redZone", "twoMinute"}
+
+// Is the split worked out from plays, such as down or red zone, rather than from game totals?
+func IsPlaySplit(by string) bool {
+	for _, playSplit := range playSplits {
+		if strings.EqualFold(playSplit, by) {
+			return true
+		}
+	}
+	return false
+}
+
+// Group the player's passing, rushing and receiving plays by the given split and add up the stats in each group.
+// by can be quarter, down, distance, redZone or twoMinute
+func NewPlaySplits(playerId int, plays []Play, by string) ([]Split, error) {
+	getSplitKey, err := getPlaySplitKeyFunc(by)
+	if err != nil {
+		return nil, err
+	}
+
+	var splitKeys []string
+	sortValues := make(map[string]int)
+	playStatsBySplit := make(map[string][]PlayerStats)
+
+	for _, play := range plays {
+		playStats, ok := newPlayerPlayStats(playerId, play)
+		if !ok || !play.IsScrimmage() {
+			continue
+		}
+
+		splitKey, sortValue := getSplitKey(play)
+		if _, exists := playStatsBySplit[splitKey]; !exists {
+			splitKeys = append(splitKeys, splitKey)
+			sortValues[splitKey] = sortValue
+		}
+		playStatsBySplit[splitKey] = append(playStatsBySplit[splitKey], playStats)
+	}
+
+	sort.SliceStable(splitKeys, func(i, j int) bool {
+		return sortValues[splitKeys[i]] < sortValues[splitKeys[j]]
+	})
+
+	splits := []Split{}
+	for _, splitKey := range splitKeys {
+		summary := NewPlayerStatsSummary(playStatsBySplit[splitKey])
+		summary.Games = countGames(playStatsBySplit[splitKey])
+		metrics := NewMetrics(summary.StatLine)
+		summary.Metrics = &metrics
+
+		splits = append(splits, Split {
+			Split: splitKey,
+			PlayerStatsSummary: summary,
+		})
+	}
+
+	return splits, nil
+}
+
+// Get the function that returns the split a play belongs in and a value to order the splits by
+func getPlaySplitKeyFunc(by string) (func(Play) (string, int), error) {
+	switch strings.ToLower(by) {
+	case "quarter":
+		return func(play Play) (string, int) {
+			if play.Quarter > 4 {
+				return "OT", 5
+			}
+			return strconv.Itoa(play.Quarter), play.Quarter
+		}, nil
+	case "down":
+		return func(play Play) (string, int) {
+			return strconv.Itoa(play.Down), play.Down
+		}, nil
+	case "distance":
+		return func(play Play) (string, int) {
+			distanceBucket := getDistanceBucket(play.YardsToGo)
+			return distanceBucket, strings.Index("short medium long veryLong", distanceBucket)
+		}, nil
+	case "redzone":
+		return func(play Play) (string, int) {
+			if play.IsRedZone() {
+				return "redZone", 0
+			}
+			return "outsideRedZone", 1
+		}, nil
+	case "twominute":
+		return func(play Play) (string, int) {
+			if play.IsTwoMinuteDrill() {
+				return "twoMinuteDrill", 0
+			}
+			return "other", 1
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown split: %v. Use %v", by, strings.Join(playSplits, ", "))
+}
+
+// Get the player's passing, rushing and receiving stats from a play as a stat line, and whether they were in it
+func newPlayerPlayStats(playerId int, play Play) (PlayerStats, bool) {
+	line := make(StatLine)
+	playStats := PlayerStats {
+		PlayerId: playerId,
+		GameContext: GameContext{GameKey: play.GameKey},
+	}
+	isInPlay := false
+
+	for _, player := range play.Players {
+		if player.PlayerId != playerId {
+			continue
+		}
+
+		playStats.Name = player.Name
+		playStats.TeamAbbr = player.TeamAbbr
+		switch player.GetRole() {
+		case PlayRolePasser:
+			// Sacks aren't pass attempts
+			if player.StatId == statIdSack {
+				continue
+			}
+			isComplete := player.StatId == statIdCompletePass || player.StatId == statIdCompletePassTd
+			yards := 0.0
+			if isComplete {
+				yards = float64(player.Yards)
+			}
+			addToStatLine(line, "passing", map[string]float64{"att": 1, "cmp": getCount(isComplete), "yds": yards,
+				"tds": getCount(player.StatId == statIdCompletePassTd), "ints": getCount(player.StatId == statIdInterception)})
+		case PlayRoleRusher:
+			addToStatLine(line, "rushing", map[string]float64{"att": 1, "yds": float64(player.Yards),
+				"tds": getCount(player.StatId == statIdRushTd)})
+		case PlayRoleReceiver:
+			// Targets without a catch aren't receiving stats
+			if player.StatId == statIdTarget {
+				continue
+			}
+			addToStatLine(line, "receiving", map[string]float64{"rec": 1, "yds": float64(player.Yards),
+				"tds": getCount(player.StatId == statIdReceptionTd)})
+		default:
+			continue
+		}
+		isInPlay = true
+	}
+
+	playStats.StatLine = line
+	return playStats, isInPlay
+}
+
+// Add the values to the stats of a category in a stat line
+func addToStatLine(line StatLine, statsType string, values map[string]float64) {
+	if line[statsType] == nil {
+		line[statsType] = make(CategoryStats)
+	}
+	for label, value := range values {
+		line[statsType][label] += value
+	}
+}
+
+// Count a stat once when it happened on the play
+func getCount(isCounted bool) float64 {
+	if isCounted {
+		return 1
+	}
+	return 0
+}
+
+// Count the different games in the stats
+func countGames(playerStats []PlayerStats) int {
+	isCounted := make(map[string]bool)
+	for _, stats := range playerStats {
+		isCounted[stats.GameKey] = true
+	}
+	return len(isCounted)
+}
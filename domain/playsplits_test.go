@@ -0,0 +1,69 @@
+package domain
+
+import "testing"
+
+func TestNewPlaySplitsByDown(t *testing.T) {
+	passer := 1
+	receiver := 2
+	newPlay := func(gameKey string, down int, players ...PlayPlayer) Play {
+		return Play{GameKey: gameKey, TeamAbbr: "NE", Down: down, YardsToGo: 10, Players: players}
+	}
+
+	plays := []Play {
+		newPlay("a", 1, PlayPlayer{PlayerId: passer, StatId: statIdCompletePass, Yards: 12},
+			PlayPlayer{PlayerId: receiver, StatId: statIdReception, Yards: 12}),
+		newPlay("a", 1, PlayPlayer{PlayerId: passer, StatId: statIdIncompletePass},
+			PlayPlayer{PlayerId: receiver, StatId: statIdTarget}),
+		newPlay("b", 3, PlayPlayer{PlayerId: passer, StatId: statIdCompletePassTd, Yards: 20},
+			PlayPlayer{PlayerId: receiver, StatId: statIdReceptionTd, Yards: 20}),
+		newPlay("b", 3, PlayPlayer{PlayerId: passer, StatId: statIdSack, Yards: -7}),
+		newPlay("b", 2, PlayPlayer{PlayerId: passer, StatId: statIdInterception}),
+	}
+
+	tests := []struct {
+		playerId int
+		split string
+		statKey string
+		expected float64
+		games int
+	}{
+		{passer, "1", "passing.att", 2, 1},
+		{passer, "1", "passing.cmp", 1, 1},
+		{passer, "1", "passing.yds", 12, 1},
+		{passer, "2", "passing.ints", 1, 1},
+		{passer, "3", "passing.att", 1, 1},
+		{passer, "3", "passing.tds", 1, 1},
+		{receiver, "1", "receiving.rec", 1, 1},
+		{receiver, "3", "receiving.tds", 1, 1},
+		{receiver, "3", "receiving.yds", 20, 1},
+	}
+
+	for _, test := range tests {
+		splits, err := NewPlaySplits(test.playerId, plays, "down")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, split := range splits {
+			if split.Split != test.split {
+				continue
+			}
+			found = true
+			value, _ := split.StatLine.GetStat(test.statKey)
+			if value != test.expected || split.Games != test.games {
+				t.Errorf("player %v down %v: %v = %v in %v games, expected %v in %v games", test.playerId,
+					test.split, test.statKey, value, split.Games, test.expected, test.games)
+			}
+		}
+		if !found {
+			t.Errorf("player %v has no split for down %v", test.playerId, test.split)
+		}
+	}
+}
+
+func TestNewPlaySplitsUnknownSplit(t *testing.T) {
+	if _, err := NewPlaySplits(1, nil, "weather"); err == nil {
+		t.Error("expected an error for an unknown split")
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Offensive positions for fantasy purposes
+const (
+	PositionQuarterback = "QB"
+	PositionRunningBack = "RB"
+	PositionWideReceiver = "WR"
+	PositionTightEnd = "TE"
+)
+
+// Where a player's position came from, from the most trusted
+const (
+	PositionSourceOverride = "override"
+	PositionSourceRoster = "roster"
+	PositionSourceInferred = "inferred"
+)
+
+// Positions are short abbreviations such as QB, OLB or LS
+var positionPattern = regexp.MustCompile(`^[A-Z]{1,4}$`)
+
+// A player's position where it's been set, by a manual override or an imported roster
+type KnownPosition struct {
+	Override string
+	Roster string
+}
+
+// Get the player's position and where it came from. An override wins over the roster,
+// and the stats are only used when neither has been set
+func GetPosition(known KnownPosition, line StatLine) (string, string) {
+	if len(known.Override) > 0 {
+		return known.Override, PositionSourceOverride
+	}
+	if len(known.Roster) > 0 {
+		return known.Roster, PositionSourceRoster
+	}
+
+	position := InferPosition(line)
+	if len(position) == 0 {
+		return "", ""
+	}
+	return position, PositionSourceInferred
+}
+
+// Get the position a player's stats look like, or "" if they have no passing, rushing or receiving stats.
+// The stats can't tell tight ends from wide receivers, so pass catchers are WRs
+func InferPosition(line StatLine) string {
+	passingAttempts := line.Get("passing", "att")
+	rushingAttempts := line.Get("rushing", "att")
+	receptions := line.Get("receiving", "rec")
+
+	switch {
+	case passingAttempts > 0 && passingAttempts >= rushingAttempts + receptions:
+		return PositionQuarterback
+	case rushingAttempts > 0 && rushingAttempts >= receptions:
+		return PositionRunningBack
+	case receptions > 0:
+		return PositionWideReceiver
+	}
+	return ""
+}
+
+// Has any player been given the position by an override or a roster? Tight ends can't be inferred from stats,
+// so without one no player is a TE
+func HasKnownPosition(knownPositions map[int]KnownPosition, position string) bool {
+	for _, known := range knownPositions {
+		if known.Override == position || (len(known.Override) == 0 && known.Roster == position) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the fantasy position for a name such as "rb", and whether it's one of QB, RB, WR or TE
+func ParsePosition(position string) (string, bool) {
+	position = strings.ToUpper(position)
+	switch position {
+	case PositionQuarterback, PositionRunningBack, PositionWideReceiver, PositionTightEnd:
+		return position, true
+	}
+	return "", false
+}
+
+// Get any position, such as "olb" from a roster, in upper case, and whether it looks like a position
+func NormalizePosition(position string) (string, bool) {
+	position = strings.ToUpper(strings.TrimSpace(position))
+	return position, positionPattern.MatchString(position)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "testing"
+
+func TestInferPosition(t *testing.T) {
+	tests := []struct {
+		name string
+		line StatLine
+		expectedPosition string
+	}{
+		{"no stats", StatLine{}, ""},
+		{"mostly passing", StatLine{"passing": {"att": 30}, "rushing": {"att": 5}}, PositionQuarterback},
+		{"running quarterback", StatLine{"passing": {"att": 20}, "rushing": {"att": 12}}, PositionQuarterback},
+		{"mostly rushing", StatLine{"rushing": {"att": 15}, "receiving": {"rec": 3}}, PositionRunningBack},
+		{"trick play pass", StatLine{"passing": {"att": 1}, "rushing": {"att": 15}}, PositionRunningBack},
+		{"mostly catching", StatLine{"rushing": {"att": 1}, "receiving": {"rec": 6}}, PositionWideReceiver},
+		{"only kicking", StatLine{"kicking": {"fga": 3}}, ""},
+	}
+
+	for _, test := range tests {
+		if position := InferPosition(test.line); position != test.expectedPosition {
+			t.Errorf("%v: got %v, expected %v", test.name, position, test.expectedPosition)
+		}
+	}
+}
+
+func TestGetPosition(t *testing.T) {
+	receiverLine := StatLine{"receiving": {"rec": 6}}
+	tests := []struct {
+		name string
+		known KnownPosition
+		line StatLine
+		expectedPosition string
+		expectedSource string
+	}{
+		{"override wins", KnownPosition{Override: "TE", Roster: "WR"}, receiverLine, "TE", PositionSourceOverride},
+		{"roster over stats", KnownPosition{Roster: "TE"}, receiverLine, "TE", PositionSourceRoster},
+		{"inferred", KnownPosition{}, receiverLine, "WR", PositionSourceInferred},
+		{"unknown", KnownPosition{}, StatLine{}, "", ""},
+	}
+
+	for _, test := range tests {
+		position, source := GetPosition(test.known, test.line)
+		if position != test.expectedPosition || source != test.expectedSource {
+			t.Errorf("%v: got %v from %v, expected %v from %v", test.name, position, source,
+				test.expectedPosition, test.expectedSource)
+		}
+	}
+}
+
+func TestHasKnownPosition(t *testing.T) {
+	tests := []struct {
+		name string
+		knownPositions map[int]KnownPosition
+		expected bool
+	}{
+		{"no known positions", nil, false},
+		{"roster tight end", map[int]KnownPosition{1: {Roster: "TE"}}, true},
+		{"overridden tight end", map[int]KnownPosition{1: {Override: "TE", Roster: "WR"}}, true},
+		{"tight end overridden away", map[int]KnownPosition{1: {Override: "WR", Roster: "TE"}}, false},
+	}
+
+	for _, test := range tests {
+		if hasKnown := HasKnownPosition(test.knownPositions, PositionTightEnd); hasKnown != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, hasKnown, test.expected)
+		}
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	tests := []struct {
+		position string
+		expectedFantasy string
+		expectedIsFantasy bool
+		expectedNormalized string
+		expectedIsPosition bool
+	}{
+		{"rb", "RB", true, "RB", true},
+		{" olb ", "", false, "OLB", true},
+		{"TE", "TE", true, "TE", true},
+		{"tight end", "", false, "TIGHT END", false},
+		{"", "", false, "", false},
+	}
+
+	for _, test := range tests {
+		fantasy, isFantasy := ParsePosition(test.position)
+		if fantasy != test.expectedFantasy || isFantasy != test.expectedIsFantasy {
+			t.Errorf("ParsePosition(%q): got %v %v", test.position, fantasy, isFantasy)
+		}
+		normalized, isPosition := NormalizePosition(test.position)
+		if normalized != test.expectedNormalized || isPosition != test.expectedIsPosition {
+			t.Errorf("NormalizePosition(%q): got %v %v", test.position, normalized, isPosition)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A player from an imported roster, keyed by the player id used in the feed
+type RosterEntry struct {
+	NflId string `json:"nflId"`
+	Position string `json:"position"`
+	FullName string `json:"fullName"`
+	// Other names the player is searched by, such as nicknames
+	Aliases []string `json:"aliases"`
+}
+
+// Aliases in a roster csv are separated by this in their column
+const rosterCsvAliasSeparator = "|"
+
+// Read a roster csv with a header row. nflId is required, and position, fullName and aliases are optional
+func ParseRosterCsv(reader io.Reader) ([]RosterEntry, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("roster has no header row")
+	}
+
+	columnIndexes := make(map[string]int)
+	for index, column := range records[0] {
+		columnIndexes[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+	if _, ok := columnIndexes["nflid"]; !ok {
+		return nil, fmt.Errorf("roster has no nflId column")
+	}
+
+	getValue := func(record []string, column string) string {
+		index, ok := columnIndexes[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var entries []RosterEntry
+	for _, record := range records[1:] {
+		entry := RosterEntry {
+			NflId: getValue(record, "nflid"),
+			Position: getValue(record, "position"),
+			FullName: getValue(record, "fullname"),
+		}
+		for _, alias := range strings.Split(getValue(record, "aliases"), rosterCsvAliasSeparator) {
+			if len(strings.TrimSpace(alias)) > 0 {
+				entry.Aliases = append(entry.Aliases, strings.TrimSpace(alias))
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Check that every entry has a feed player id and a position or full name, and put the positions in upper case
+func ValidateRoster(entries []RosterEntry) ([]RosterEntry, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("roster has no players")
+	}
+
+	validated := make([]RosterEntry, len(entries))
+	for i, entry := range entries {
+		if len(entry.NflId) == 0 {
+			return nil, fmt.Errorf("roster entry %v has no nflId", i + 1)
+		}
+		if len(entry.Position) == 0 && len(strings.TrimSpace(entry.FullName)) == 0 {
+			return nil, fmt.Errorf("roster entry %v needs a position or a fullName", i + 1)
+		}
+
+		if len(entry.Position) > 0 {
+			position, ok := NormalizePosition(entry.Position)
+			if !ok {
+				return nil, fmt.Errorf("roster entry %v has an unknown position: %v", i + 1, entry.Position)
+			}
+			entry.Position = position
+		}
+		entry.FullName = strings.TrimSpace(entry.FullName)
+		validated[i] = entry
+	}
+	return validated, nil
+}
+
+// Get the name the way the feed writes it, such as "T.Brady" for "Tom Brady", or "" for a single name
+func GetAbbreviatedName(fullName string) string {
+	names := strings.Fields(fullName)
+	if len(names) < 2 {
+		return ""
+	}
+	// The first letter rather than the first byte, so names like "Émile Smith" become "É.Smith"
+	return string([]rune(names[0])[:1]) + "." + strings.Join(names[1:], " ")
+}
+
+// Get the names a search should match: the text itself, and for text like "Tom Brady" or "T. Brady",
+// the way the feed writes it
+func GetNameSearchForms(searchText string) []string {
+	searchText = strings.TrimSpace(searchText)
+	forms := []string{searchText}
+
+	abbreviated := GetAbbreviatedName(strings.Replace(searchText, ".", ". ", 1))
+	if len(abbreviated) > 0 && abbreviated != searchText {
+		forms = append(forms, abbreviated)
+	}
+	return forms
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRosterCsv(t *testing.T) {
+	tests := []struct {
+		name string
+		csv string
+		expected []RosterEntry
+		isError bool
+	}{
+		{
+			name: "every column",
+			csv: "nflId,position,fullName,aliases\n00-0019596,QB,Tom Brady,TB12|Tommy\n",
+			expected: []RosterEntry{{NflId: "00-0019596", Position: "QB", FullName: "Tom Brady", Aliases: []string{"TB12", "Tommy"}}},
+		},
+		{
+			name: "columns in any order and case, with optional columns left out",
+			csv: " Position ,NFLID\nte,00-0030506\n",
+			expected: []RosterEntry{{NflId: "00-0030506", Position: "te"}},
+		},
+		{
+			name: "empty aliases are skipped",
+			csv: "nflId,aliases\n00-0019596, | TB12 |\n",
+			expected: []RosterEntry{{NflId: "00-0019596", Aliases: []string{"TB12"}}},
+		},
+		{name: "no header", csv: "", isError: true},
+		{name: "no nflId column", csv: "position,fullName\nQB,Tom Brady\n", isError: true},
+		{name: "uneven rows", csv: "nflId,position\n00-0019596\n", isError: true},
+	}
+
+	for _, test := range tests {
+		entries, err := ParseRosterCsv(strings.NewReader(test.csv))
+		if (err != nil) != test.isError {
+			t.Errorf("%v: got error %v, expected error %v", test.name, err, test.isError)
+			continue
+		}
+		if !test.isError && !reflect.DeepEqual(entries, test.expected) {
+			t.Errorf("%v: got %+v, expected %+v", test.name, entries, test.expected)
+		}
+	}
+}
+
+func TestValidateRoster(t *testing.T) {
+	tests := []struct {
+		name string
+		entries []RosterEntry
+		expected []RosterEntry
+		isError bool
+	}{
+		{
+			name: "positions are upper cased and names trimmed",
+			entries: []RosterEntry{{NflId: "1", Position: " olb", FullName: " Von Miller "}},
+			expected: []RosterEntry{{NflId: "1", Position: "OLB", FullName: "Von Miller"}},
+		},
+		{
+			name: "a full name without a position",
+			entries: []RosterEntry{{NflId: "1", FullName: "Tom Brady"}},
+			expected: []RosterEntry{{NflId: "1", FullName: "Tom Brady"}},
+		},
+		{name: "no entries", isError: true},
+		{name: "no nflId", entries: []RosterEntry{{Position: "QB"}}, isError: true},
+		{name: "no position or full name", entries: []RosterEntry{{NflId: "1", FullName: " "}}, isError: true},
+		{name: "unknown position", entries: []RosterEntry{{NflId: "1", Position: "Quarterback"}}, isError: true},
+	}
+
+	for _, test := range tests {
+		validated, err := ValidateRoster(test.entries)
+		if (err != nil) != test.isError {
+			t.Errorf("%v: got error %v, expected error %v", test.name, err, test.isError)
+			continue
+		}
+		if !test.isError && !reflect.DeepEqual(validated, test.expected) {
+			t.Errorf("%v: got %+v, expected %+v", test.name, validated, test.expected)
+		}
+	}
+}
+
+func TestGetNameSearchForms(t *testing.T) {
+	tests := []struct {
+		searchText string
+		expected []string
+	}{
+		{"Tom Brady", []string{"Tom Brady", "T.Brady"}},
+		{"T. Brady", []string{"T. Brady", "T.Brady"}},
+		{"T.Brady", []string{"T.Brady"}},
+		{"Brady", []string{"Brady"}},
+		{" Odell Beckham Jr. ", []string{"Odell Beckham Jr.", "O.Beckham Jr."}},
+		{"Émile Smith", []string{"Émile Smith", "É.Smith"}},
+		{"Ja'Marr Chase", []string{"Ja'Marr Chase", "J.Chase"}},
+	}
+
+	for _, test := range tests {
+		if forms := GetNameSearchForms(test.searchText); !reflect.DeepEqual(forms, test.expected) {
+			t.Errorf("%q: got %q, expected %q", test.searchText, forms, test.expected)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// A game from the league's published schedule. Unlike the feed, the schedule has games that haven't been played
+type ScheduledGame struct {
+	Game
+	// P before the game, the quarter while it's on and F, or FO after overtime, once it's over
+	Status string `json:"status"`
+}
+
+// Has the schedule marked the game as over?
+func (game ScheduledGame) IsOver() bool {
+	return strings.HasPrefix(game.Status, "F")
+}
+
+// Get the regular season games in the schedule that haven't been played by now, in the order they're played
+func GetRemainingGames(schedule []ScheduledGame, now time.Time) []Game {
+	remainingGames := []Game{}
+	for _, scheduledGame := range schedule {
+		if scheduledGame.IsRegularSeason() && !scheduledGame.IsOver() && !scheduledGame.IsFinished(now) {
+			remainingGames = append(remainingGames, scheduledGame.Game)
+		}
+	}
+	return remainingGames
+}
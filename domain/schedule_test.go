@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetRemainingGames(t *testing.T) {
+	now := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+	newScheduledGame := func(week int, status string) ScheduledGame {
+		return ScheduledGame{Game: newTestGame(2018, week, "NE", "MIA"), Status: status}
+	}
+
+	tests := []struct {
+		name string
+		game ScheduledGame
+		isRemaining bool
+	}{
+		{"played", newScheduledGame(1, "F"), false},
+		{"played in overtime", newScheduledGame(2, "FO"), false},
+		{"past but not marked over", newScheduledGame(3, "P"), false},
+		{"upcoming", newScheduledGame(5, "P"), true},
+		{"marked over early", newScheduledGame(6, "F"), false},
+		{"playoffs", newScheduledGame(18, "P"), false},
+	}
+
+	for _, test := range tests {
+		remainingGames := GetRemainingGames([]ScheduledGame{test.game}, now)
+		if (len(remainingGames) == 1) != test.isRemaining {
+			t.Errorf("%v: got %v remaining games, expected remaining %v", test.name, len(remainingGames), test.isRemaining)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A named set of rules used to score a stat line in fantasy points
+type ScoringRuleset struct {
+	Name string `json:"name"`
+	// Points awarded per unit of a stat, keyed by stat key such as "rushing.yds"
+	Points map[string]float64 `json:"points"`
+	Bonuses []ScoringBonus `json:"bonuses"`
+	IsBuiltIn bool `json:"isBuiltIn"`
+}
+
+// Points awarded once when a stat reaches the threshold in a game, such as a 100+ yard game
+type ScoringBonus struct {
+	Stat string `json:"stat"`
+	Threshold int `json:"threshold"`
+	Points float64 `json:"points"`
+}
+
+// Get the rulesets that are always available
+func GetBuiltInScoringRulesets() []ScoringRuleset {
+	standard := newBuiltInRuleset("standard", 0, 4)
+	halfPpr := newBuiltInRuleset("half-ppr", 0.5, 4)
+	ppr := newBuiltInRuleset("ppr", 1, 4)
+	sixPointPassingTd := newBuiltInRuleset("6pt-passing-td", 0, 6)
+
+	return []ScoringRuleset {
+		standard,
+		halfPpr,
+		ppr,
+		sixPointPassingTd,
+	}
+}
+
+// Get a built in ruleset by name
+func GetBuiltInScoringRuleset(name string) (ScoringRuleset, bool) {
+	for _, ruleset := range GetBuiltInScoringRulesets() {
+		if strings.EqualFold(ruleset.Name, name) {
+			return ruleset, true
+		}
+	}
+	return ScoringRuleset{}, false
+}
+
+// Create a ruleset with the standard yardage and touchdown points
+func newBuiltInRuleset(name string, pointsPerReception float64, pointsPerPassingTd float64) ScoringRuleset {
+	points := map[string]float64 {
+		"passing.yds": 0.04,
+		"passing.tds": pointsPerPassingTd,
+		"passing.ints": -2,
+		"passing.twoptm": 2,
+		"rushing.yds": 0.1,
+		"rushing.tds": 6,
+		"rushing.twoptm": 2,
+		"receiving.yds": 0.1,
+		"receiving.tds": 6,
+		"receiving.twoptm": 2,
+		"kicking.fgm": 3,
+		"kicking.xpmade": 1,
+		"fumbles.lost": -2,
+		"kickret.tds": 6,
+		"puntret.tds": 6,
+	}
+
+	if pointsPerReception != 0 {
+		points["receiving.rec"] = pointsPerReception
+	}
+
+	return ScoringRuleset {
+		Name: name,
+		Points: points,
+		Bonuses: []ScoringBonus{},
+		IsBuiltIn: true,
+	}
+}
+
+// Score the stat line in fantasy points
+func (ruleset ScoringRuleset) Score(line StatLine) float64 {
+	total := 0.0
+
+	for statKey, points := range ruleset.Points {
+		value, ok := line.GetStat(statKey)
+		if ok {
+			total += value * points
+		}
+	}
+
+	for _, bonus := range ruleset.Bonuses {
+		value, ok := line.GetStat(bonus.Stat)
+		if ok && value >= float64(bonus.Threshold) {
+			total += bonus.Points
+		}
+	}
+
+	return math.Round(total * 100) / 100
+}
+
+// Score each game and add up the points. Bonuses are awarded per game so totals can't be scored directly
+func (ruleset ScoringRuleset) ScoreGames(playerStats []PlayerStats) float64 {
+	total := 0.0
+	for _, gameStats := range playerStats {
+		total += ruleset.Score(gameStats.StatLine)
+	}
+	return math.Round(total * 100) / 100
+}
+
+// Check that the ruleset has a name and only refers to known stats
+func (ruleset ScoringRuleset) Validate() error {
+	if len(strings.TrimSpace(ruleset.Name)) == 0 {
+		return fmt.Errorf("ruleset name is required")
+	}
+
+	for statKey := range ruleset.Points {
+		if !IsStatKey(statKey) {
+			return fmt.Errorf("unknown stat in points: %v", statKey)
+		}
+	}
+
+	for _, bonus := range ruleset.Bonuses {
+		if !IsStatKey(bonus.Stat) {
+			return fmt.Errorf("unknown stat in bonuses: %v", bonus.Stat)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	line := StatLine{
+		"passing": {"yds": 250, "tds": 2, "ints": 1},
+		"rushing": {"yds": 34, "tds": 1},
+		"receiving": {"rec": 4, "yds": 20},
+		"fumbles": {"lost": 1},
+	}
+	// 10 passing yards, 3.4 rushing yards and 2 receiving yards, with 6 for the rushing td
+	// and -2 each for the interception and the lost fumble
+	yardsPoints := 10 + 3.4 + 2 + 6 - 2 - 2
+
+	tests := []struct {
+		ruleset string
+		expected float64
+	}{
+		{"standard", yardsPoints + 8},
+		{"half-ppr", yardsPoints + 8 + 2},
+		{"ppr", yardsPoints + 8 + 4},
+		{"6pt-passing-td", yardsPoints + 12},
+	}
+
+	for _, test := range tests {
+		ruleset, ok := GetBuiltInScoringRuleset(test.ruleset)
+		if !ok {
+			t.Fatalf("no built in ruleset named %v", test.ruleset)
+		}
+		if points := ruleset.Score(line); points != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.ruleset, points, test.expected)
+		}
+	}
+}
+
+func TestScoreBonuses(t *testing.T) {
+	ruleset := ScoringRuleset{
+		Name: "bonuses",
+		Points: map[string]float64{"rushing.yds": 0.1},
+		Bonuses: []ScoringBonus{{Stat: "rushing.yds", Threshold: 100, Points: 3}},
+	}
+
+	tests := []struct {
+		name string
+		line StatLine
+		expected float64
+	}{
+		{"under the threshold", StatLine{"rushing": {"yds": 99}}, 9.9},
+		{"at the threshold", StatLine{"rushing": {"yds": 100}}, 13},
+		{"over the threshold", StatLine{"rushing": {"yds": 150}}, 18},
+		{"no stat", StatLine{}, 0},
+	}
+
+	for _, test := range tests {
+		if points := ruleset.Score(test.line); points != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.name, points, test.expected)
+		}
+	}
+
+	// Two 60 yard games add up to 120 yards, but neither game earns the bonus
+	games := []PlayerStats{
+		{StatLine: StatLine{"rushing": {"yds": 60}}},
+		{StatLine: StatLine{"rushing": {"yds": 60}}},
+	}
+	if points := ruleset.ScoreGames(games); points != 12 {
+		t.Errorf("two 60 yard games: got %v, expected 12", points)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		ruleset ScoringRuleset
+		isError bool
+	}{
+		{"valid", ScoringRuleset{Name: "league", Points: map[string]float64{"rushing.yds": 0.1},
+			Bonuses: []ScoringBonus{{Stat: "receiving.yds", Threshold: 100, Points: 3}}}, false},
+		{"no name", ScoringRuleset{Name: " ", Points: map[string]float64{"rushing.yds": 0.1}}, true},
+		{"unknown points stat", ScoringRuleset{Name: "league", Points: map[string]float64{"rushing.yards": 0.1}}, true},
+		{"unknown bonus stat", ScoringRuleset{Name: "league", Bonuses: []ScoringBonus{{Stat: "yds", Threshold: 100}}}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.ruleset.Validate(); (err != nil) != test.isError {
+			t.Errorf("%v: got error %v, expected error %v", test.name, err, test.isError)
+		}
+	}
+
+	for _, ruleset := range GetBuiltInScoringRulesets() {
+		if err := ruleset.Validate(); err != nil {
+			t.Errorf("built in ruleset %v: %v", ruleset.Name, err)
+		}
+	}
+}
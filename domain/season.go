@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Get the season a game date belongs to. Games in January and February are part of the previous year's season
+func GetSeason(gameDate time.Time) int {
+	if gameDate.Month() < time.March {
+		return gameDate.Year() - 1
+	}
+	return gameDate.Year()
+}
+
+// Get the week of the season for a game date. Preseason games are week 0
+func GetWeek(gameDate time.Time) int {
+	seasonStart := getSeasonStartDate(GetSeason(gameDate))
+	date := time.Date(gameDate.Year(), gameDate.Month(), gameDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	if date.Before(seasonStart) {
+		return 0
+	}
+
+	daysIntoSeason := int(date.Sub(seasonStart).Hours() / 24)
+	return daysIntoSeason / 7 + 1
+}
+
+// Get the first and last dates of the regular season and playoffs for a season
+func GetSeasonDateRange(season int) (time.Time, time.Time) {
+	seasonStart := getSeasonStartDate(season)
+	seasonEnd := time.Date(season + 1, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	return seasonStart, seasonEnd
+}
+
+// Get the first and last dates of a week in a season
+func GetWeekDateRange(season int, week int) (time.Time, time.Time) {
+	weekStart := getSeasonStartDate(season).AddDate(0, 0, (week - 1) * 7)
+	return weekStart, weekStart.AddDate(0, 0, 6)
+}
+
+// Weeks start on the Tuesday after Labor Day, so the Thursday opener is in week 1
+func getSeasonStartDate(season int) time.Time {
+	laborDay := time.Date(season, time.September, 1, 0, 0, 0, 0, time.UTC)
+	for laborDay.Weekday() != time.Monday {
+		laborDay = laborDay.AddDate(0, 0, 1)
+	}
+	return laborDay.AddDate(0, 0, 1)
+}
+
+// Get the number of weeks in a season's regular season: 17 until 2021 added an 18th. Later weeks are the playoffs
+func GetRegularSeasonWeeks(season int) int {
+	if season >= 2021 {
+		return 18
+	}
+	return 17
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A player's stats aggregated over the games in one split, such as home games
+type Split struct {
+	Split string `json:"split"`
+	PlayerStatsSummary
+}
+
+// Write the split's summary with the split's name added to it
+func (split Split) MarshalJSON() ([]byte, error) {
+	return marshalWithFields(split.PlayerStatsSummary, map[string]interface{} {
+		"split": split.Split,
+	})
+}
+
+// Group a player's games by the given split and aggregate the stats in each group.
+// by can be homeAway, opponent, month, result or dayOfWeek. Fantasy points are added when a ruleset is given
+func NewSplits(playerStats []PlayerStats, by string, ruleset *ScoringRuleset) ([]Split, error) {
+	getSplitKey, err := getSplitKeyFunc(by)
+	if err != nil {
+		return nil, err
+	}
+
+	var splitKeys []string
+	sortValues := make(map[string]int)
+	gamesBySplit := make(map[string][]PlayerStats)
+
+	for _, gameStats := range playerStats {
+		splitKey, sortValue, ok := getSplitKey(gameStats)
+		if !ok {
+			continue
+		}
+
+		if _, exists := gamesBySplit[splitKey]; !exists {
+			splitKeys = append(splitKeys, splitKey)
+			sortValues[splitKey] = sortValue
+		}
+		gamesBySplit[splitKey] = append(gamesBySplit[splitKey], gameStats)
+	}
+
+	sort.SliceStable(splitKeys, func(i, j int) bool {
+		if sortValues[splitKeys[i]] == sortValues[splitKeys[j]] {
+			return splitKeys[i] < splitKeys[j]
+		}
+		return sortValues[splitKeys[i]] < sortValues[splitKeys[j]]
+	})
+
+	splits := []Split{}
+	for _, splitKey := range splitKeys {
+		summary := NewPlayerStatsSummary(gamesBySplit[splitKey])
+		metrics := NewMetrics(summary.StatLine)
+		summary.Metrics = &metrics
+
+		if ruleset != nil {
+			points := ruleset.ScoreGames(gamesBySplit[splitKey])
+			summary.FantasyPoints = &points
+		}
+
+		splits = append(splits, Split {
+			Split: splitKey,
+			PlayerStatsSummary: summary,
+		})
+	}
+
+	return splits, nil
+}
+
+// Get the function that returns the split a game belongs in, a value to order the splits by,
+// and whether the game has what's needed to be split
+func getSplitKeyFunc(by string) (func(PlayerStats) (string, int, bool), error) {
+	switch strings.ToLower(by) {
+	case "homeaway":
+		return func(gameStats PlayerStats) (string, int, bool) {
+			if gameStats.IsHome {
+				return "home", 0, hasGameContext(gameStats)
+			}
+			return "away", 1, hasGameContext(gameStats)
+		}, nil
+	case "opponent":
+		return func(gameStats PlayerStats) (string, int, bool) {
+			return gameStats.Opponent, 0, hasGameContext(gameStats)
+		}, nil
+	case "month":
+		return func(gameStats PlayerStats) (string, int, bool) {
+			// Order months by the season, so September comes before January
+			month := gameStats.GameDate.Month()
+			sortValue := int(month)
+			if month < time.March {
+				sortValue += 12
+			}
+			return month.String(), sortValue, true
+		}, nil
+	case "result":
+		return func(gameStats PlayerStats) (string, int, bool) {
+			return gameStats.Result, strings.Index("WLT", gameStats.Result), hasGameContext(gameStats)
+		}, nil
+	case "dayofweek":
+		return func(gameStats PlayerStats) (string, int, bool) {
+			weekday := gameStats.GameDate.Weekday()
+			return weekday.String(), int(weekday), true
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown split: %v. Use homeAway, opponent, month, result or dayOfWeek", by)
+}
+
+// Check if the game stats have the opponent and result of the game
+func hasGameContext(gameStats PlayerStats) bool {
+	return len(gameStats.GameKey) > 0
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSplits(t *testing.T) {
+	newGameStats := func(gameDate time.Time, homeAbbr string, awayAbbr string, homeScore int, awayScore int,
+		yards float64) PlayerStats {
+		gameStats := PlayerStats{PlayerId: 1, Name: "L.Blount", TeamAbbr: "NE", GameDate: gameDate,
+			StatLine: StatLine{"rushing": {"att": 10, "yds": yards}}}
+		gameStats.SetGame(NewGame(gameDate.Format("20060102") + "00", gameDate, homeAbbr, awayAbbr, homeScore,
+			awayScore))
+		return gameStats
+	}
+	playerStats := []PlayerStats{
+		// Thursday
+		newGameStats(time.Date(2018, time.September, 6, 0, 0, 0, 0, time.UTC), "NE", "HOU", 27, 20, 50),
+		newGameStats(time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC), "JAX", "NE", 31, 20, 30),
+		newGameStats(time.Date(2018, time.October, 14, 0, 0, 0, 0, time.UTC), "NE", "KC", 43, 40, 80),
+		newGameStats(time.Date(2018, time.December, 30, 0, 0, 0, 0, time.UTC), "NE", "NYJ", 38, 38, 20),
+		newGameStats(time.Date(2019, time.January, 13, 0, 0, 0, 0, time.UTC), "NE", "LAC", 41, 28, 100),
+		// A game without its game context, which only the date splits can use
+		{PlayerId: 1, Name: "L.Blount", TeamAbbr: "NE", GameDate: time.Date(2018, time.November, 4, 0, 0, 0, 0, time.UTC),
+			StatLine: StatLine{"rushing": {"att": 10, "yds": 10}}},
+	}
+
+	tests := []struct {
+		by string
+		expectedSplits []string
+		expectedGames []int
+		expectedYards []float64
+	}{
+		{"homeAway", []string{"home", "away"}, []int{4, 1}, []float64{250, 30}},
+		{"opponent", []string{"HOU", "JAX", "KC", "LAC", "NYJ"}, []int{1, 1, 1, 1, 1}, []float64{50, 30, 80, 100, 20}},
+		// The season's months in order, so January comes last
+		{"month", []string{"September", "October", "November", "December", "January"}, []int{2, 1, 1, 1, 1},
+			[]float64{80, 80, 10, 20, 100}},
+		{"result", []string{"W", "L", "T"}, []int{3, 1, 1}, []float64{230, 30, 20}},
+		{"DAYOFWEEK", []string{"Sunday", "Thursday"}, []int{5, 1}, []float64{240, 50}},
+	}
+
+	for _, test := range tests {
+		splits, err := NewSplits(playerStats, test.by, nil)
+		if err != nil {
+			t.Errorf("%v: got error %v", test.by, err)
+			continue
+		}
+
+		var names []string
+		var games []int
+		var yards []float64
+		for _, split := range splits {
+			names = append(names, split.Split)
+			games = append(games, split.Games)
+			yards = append(yards, split.StatLine.Get("rushing", "yds"))
+			if split.Metrics == nil || split.FantasyPoints != nil {
+				t.Errorf("%v %v: expected metrics and no fantasy points without a ruleset", test.by, split.Split)
+			}
+		}
+		if !reflect.DeepEqual(names, test.expectedSplits) || !reflect.DeepEqual(games, test.expectedGames) ||
+			!reflect.DeepEqual(yards, test.expectedYards) {
+			t.Errorf("%v: got %v with %v games and %v yards, expected %v with %v games and %v yards", test.by,
+				names, games, yards, test.expectedSplits, test.expectedGames, test.expectedYards)
+		}
+	}
+
+	if _, err := NewSplits(playerStats, "weather", nil); err == nil {
+		t.Errorf("weather: expected an unknown split error")
+	}
+}
+
+func TestNewSplitsFantasyPoints(t *testing.T) {
+	ruleset, _ := GetBuiltInScoringRuleset("standard")
+	playerStats := []PlayerStats{
+		{PlayerId: 1, GameDate: time.Date(2018, time.September, 9, 0, 0, 0, 0, time.UTC),
+			StatLine: StatLine{"rushing": {"att": 10, "yds": 50}}},
+		{PlayerId: 1, GameDate: time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC),
+			StatLine: StatLine{"rushing": {"att": 10, "yds": 70, "tds": 1}}},
+	}
+
+	splits, _ := NewSplits(playerStats, "month", &ruleset)
+	if len(splits) != 1 || splits[0].FantasyPoints == nil || *splits[0].FantasyPoints != 18 {
+		t.Fatalf("got %+v, expected one split with 18 fantasy points", splits)
+	}
+}
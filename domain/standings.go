@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Wins, losses and ties over a set of games
+type Record struct {
+	Wins int `json:"wins"`
+	Losses int `json:"losses"`
+	Ties int `json:"ties"`
+}
+
+// A team's place in the standings from their regular season games
+type TeamStanding struct {
+	TeamInfo
+	Record
+	WinPct float64 `json:"winPct"`
+	PointsFor int `json:"pointsFor"`
+	PointsAgainst int `json:"pointsAgainst"`
+	PointDifferential int `json:"pointDifferential"`
+	HomeRecord Record `json:"homeRecord"`
+	RoadRecord Record `json:"roadRecord"`
+	DivisionRecord Record `json:"divisionRecord"`
+	ConferenceRecord Record `json:"conferenceRecord"`
+}
+
+// The standings of the teams in a division, from first to last
+type DivisionStandings struct {
+	Conference string `json:"conference"`
+	Division string `json:"division"`
+	Teams []TeamStanding `json:"teams"`
+}
+
+// Work out every team's standing from the regular season games finished by now, grouped by division
+func NewStandings(games []Game, now time.Time) []DivisionStandings {
+	standingsByTeam := newTeamStandings(games, now)
+
+	var divisions []DivisionStandings
+	divisionIndexes := make(map[string]int)
+	for _, teamInfo := range teamInfos {
+		standing, ok := standingsByTeam[teamInfo.Abbr]
+		if !ok {
+			continue
+		}
+
+		divisionKey := teamInfo.Conference + " " + teamInfo.Division
+		if _, exists := divisionIndexes[divisionKey]; !exists {
+			divisionIndexes[divisionKey] = len(divisions)
+			divisions = append(divisions, DivisionStandings {
+				Conference: teamInfo.Conference,
+				Division: teamInfo.Division,
+			})
+		}
+
+		index := divisionIndexes[divisionKey]
+		divisions[index].Teams = append(divisions[index].Teams, standing)
+	}
+
+	for _, division := range divisions {
+		sortTeamStandings(division.Teams)
+	}
+	if divisions == nil {
+		return []DivisionStandings{}
+	}
+	return divisions
+}
+
+// Work out the standing of each team with regular season games finished by now, keyed by team.
+// Games still being played are saved with the score so far, so they're left out
+func newTeamStandings(games []Game, now time.Time) map[string]TeamStanding {
+	standingsByTeam := make(map[string]TeamStanding)
+
+	for _, game := range games {
+		if !isStandingsGame(game, now) {
+			continue
+		}
+
+		for _, teamAbbr := range []string{game.HomeAbbr, game.AwayAbbr} {
+			standing, exists := standingsByTeam[teamAbbr]
+			if !exists {
+				teamInfo, ok := GetTeamInfo(teamAbbr)
+				if !ok {
+					continue
+				}
+				standing = TeamStanding{TeamInfo: teamInfo}
+			}
+
+			opponent := game.GetOpponent(teamAbbr)
+			result := game.GetResult(teamAbbr)
+			teamScore, opponentScore := game.GetScores(teamAbbr)
+
+			standing.Record = standing.Record.add(result)
+			standing.PointsFor += teamScore
+			standing.PointsAgainst += opponentScore
+			if game.HomeAbbr == teamAbbr {
+				standing.HomeRecord = standing.HomeRecord.add(result)
+			} else {
+				standing.RoadRecord = standing.RoadRecord.add(result)
+			}
+			if IsDivisionGame(teamAbbr, opponent) {
+				standing.DivisionRecord = standing.DivisionRecord.add(result)
+			}
+			if IsConferenceGame(teamAbbr, opponent) {
+				standing.ConferenceRecord = standing.ConferenceRecord.add(result)
+			}
+
+			standing.PointDifferential = standing.PointsFor - standing.PointsAgainst
+			standing.WinPct = standing.Record.GetWinPct()
+			standingsByTeam[teamAbbr] = standing
+		}
+	}
+
+	return standingsByTeam
+}
+
+// Does the game count towards the standings? Only finished regular season games do, which leaves out the preseason
+func isStandingsGame(game Game, now time.Time) bool {
+	return game.IsRegularSeason() && game.IsFinished(now)
+}
+
+// Order standings by win percentage, then point differential
+func sortTeamStandings(standings []TeamStanding) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].WinPct == standings[j].WinPct {
+			return standings[i].PointDifferential > standings[j].PointDifferential
+		}
+		return standings[i].WinPct > standings[j].WinPct
+	})
+}
+
+// Add a game's result to the record; W, L or T
+func (record Record) add(result string) Record {
+	switch result {
+	case "W":
+		record.Wins++
+	case "L":
+		record.Losses++
+	case "T":
+		record.Ties++
+	}
+	return record
+}
+
+// Get the games won with ties counting as half a win, as a fraction rounded to three places
+func (record Record) GetWinPct() float64 {
+	games := record.Wins + record.Losses + record.Ties
+	if games == 0 {
+		return 0
+	}
+	return math.Round((float64(record.Wins) + float64(record.Ties) / 2) / float64(games) * 1000) / 1000
+}
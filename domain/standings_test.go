@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetRegularSeasonWeeks(t *testing.T) {
+	tests := []struct {
+		season int
+		expected int
+	}{
+		{2018, 17},
+		{2020, 17},
+		{2021, 18},
+		{2023, 18},
+	}
+
+	for _, test := range tests {
+		actual := GetRegularSeasonWeeks(test.season)
+		if actual != test.expected {
+			t.Errorf("GetRegularSeasonWeeks(%v) = %v, expected %v", test.season, actual, test.expected)
+		}
+	}
+}
+
+func TestIsRegularSeason(t *testing.T) {
+	tests := []struct {
+		date string
+		expected bool
+	}{
+		// Preseason
+		{"2018-08-30", false},
+		{"2018-09-06", true},
+		{"2018-12-30", true},
+		// The 2018 wild card round was week 18
+		{"2019-01-05", false},
+		// Week 18 has been in the regular season since 2021
+		{"2022-01-09", true},
+		{"2022-01-15", false},
+	}
+
+	for _, test := range tests {
+		gameDate, _ := time.Parse("2006-01-02", test.date)
+		game := NewGame("", gameDate, "NE", "MIA", 0, 0)
+		if game.IsRegularSeason() != test.expected {
+			t.Errorf("game on %v (week %v): IsRegularSeason() = %v, expected %v", test.date, game.Week,
+				game.IsRegularSeason(), test.expected)
+		}
+	}
+}
+
+func TestNewTeamStandings(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2021, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	games := []Game {
+		NewGame("1", date(time.September, 12), "LV", "BAL", 33, 27),
+		NewGame("2", date(time.September, 19), "PIT", "LV", 17, 26),
+		NewGame("3", date(time.October, 4), "LV", "LAC", 14, 28),
+		NewGame("4", date(time.December, 5), "KC", "DEN", 22, 9),
+		// Week 18
+		NewGame("5", time.Date(2022, time.January, 9, 0, 0, 0, 0, time.UTC), "LV", "LAC", 35, 32),
+		// Preseason
+		NewGame("6", date(time.August, 14), "LV", "SEA", 20, 7),
+		// Still being played on the day the standings are worked out
+		NewGame("7", time.Date(2022, time.January, 10, 0, 0, 0, 0, time.UTC), "KC", "LV", 14, 7),
+	}
+
+	standings := newTeamStandings(games, time.Date(2022, time.January, 10, 20, 0, 0, 0, time.UTC))
+	tests := []struct {
+		teamAbbr string
+		record Record
+		divisionRecord Record
+		pointDifferential int
+	}{
+		{"LV", Record{3, 1, 0}, Record{1, 1, 0}, 4},
+		{"LAC", Record{1, 1, 0}, Record{1, 1, 0}, 11},
+		{"KC", Record{1, 0, 0}, Record{1, 0, 0}, 13},
+		{"SEA", Record{}, Record{}, 0},
+	}
+
+	for _, test := range tests {
+		standing := standings[test.teamAbbr]
+		if standing.Record != test.record || standing.DivisionRecord != test.divisionRecord ||
+			standing.PointDifferential != test.pointDifferential {
+			t.Errorf("%v: %+v division %+v differential %v, expected %+v division %+v differential %v",
+				test.teamAbbr, standing.Record, standing.DivisionRecord, standing.PointDifferential,
+				test.record, test.divisionRecord, test.pointDifferential)
+		}
+	}
+}
+
+func TestGetWinPct(t *testing.T) {
+	tests := []struct {
+		record Record
+		expected float64
+	}{
+		{Record{}, 0},
+		{Record{10, 6, 0}, 0.625},
+		{Record{9, 6, 1}, 0.594},
+		{Record{0, 16, 1}, 0.029},
+	}
+
+	for _, test := range tests {
+		actual := test.record.GetWinPct()
+		if actual != test.expected {
+			t.Errorf("%+v.GetWinPct() = %v, expected %v", test.record, actual, test.expected)
+		}
+	}
+}
@@ -1,170 +1,182 @@
 package domain
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 )
 
 // All information for a particular player
 type PlayerStats struct {
+	PlayerId int `json:"playerId"`
 	Name string `json:"name"`
 	TeamAbbr string `json:"teamAbbr"`
 	GameDate time.Time `json:"gameDate"`
-	PassingStats PassingStats `json:"passingStats"`
-	RushingStats RushingStats `json:"rushingStats"`
-	ReceivingStats ReceivingStats `json:"receivingStats"`
+	GameContext
+	StatLine `json:"-"`
+	Metrics *Metrics `json:"metrics,omitempty"`
+	FantasyPoints *float64 `json:"fantasyPoints,omitempty"`
 }
 
+// Add the context of the game the stats were recorded in, from the player's team's point of view
+func (playerStats *PlayerStats) SetGame(game Game) {
+	playerStats.GameContext = NewGameContext(game, playerStats.TeamAbbr)
+}
 
-// Passing Stats for a player
-type PassingStats struct {
-	Attempts int `json:"att"`
-	Completions int `json:"cmp"`
-	Yards int `json:"yds"`
-	Touchdowns int `json:"tds"`
-	Interceptions int `json:"ints"`
-	TwoPointAttempts int `json:"twopta"`
-	TwoPointSuccesses int `json:"twoptm"`
+// Write the player's stats with each stat category under its json key, such as "passingStats"
+func (playerStats PlayerStats) MarshalJSON() ([]byte, error) {
+	type playerStatsJson PlayerStats
+	return marshalWithStatLine(playerStatsJson(playerStats), playerStats.StatLine)
 }
 
-// Rushing stats for a player
-type RushingStats struct {
-	Attempts int `json:"att"`
-	Yards int `json:"yds"`
-	Touchdowns int `json:"tds"`
-	Longest int `json:"lng"`
-	LongestTouchdown int `json:"lngtd"`
-	TwoPointAttempts int `json:"twopta"`
-	TwoPointSuccesses int `json:"twoptm"`
+// Stats for a player over one game or a set of games, keyed by stat category feed key
+type StatLine map[string]CategoryStats
+
+// Stats for one category keyed by field label. Fields that weren't in the feed are left out
+type CategoryStats map[string]float64
+
+// Aggregated stats for a player over a set of games
+type PlayerStatsSummary struct {
+	PlayerId int `json:"playerId"`
+	Name string `json:"name"`
+	TeamAbbr string `json:"teamAbbr"`
+	Games int `json:"games"`
+	StatLine `json:"-"`
+	Metrics *Metrics `json:"metrics,omitempty"`
+	FantasyPoints *float64 `json:"fantasyPoints,omitempty"`
 }
 
-// Receiving stats for a player
-type ReceivingStats struct {
-	Receptions int `json:"rec"`
-	Yards int `json:"yds"`
-	Touchdowns int `json:"tds"`
-	Longest int `json:"lng"`
-	LongestTouchdown int `json:"lngtd"`
-	TwoPointAttempts int `json:"twopta"`
-	TwoPointSuccesses int `json:"twoptm"`
+// Write the summary with each stat category under its json key, such as "passingStats"
+func (summary PlayerStatsSummary) MarshalJSON() ([]byte, error) {
+	type playerStatsSummaryJson PlayerStatsSummary
+	return marshalWithStatLine(playerStatsSummaryJson(summary), summary.StatLine)
 }
 
 // Return the stats label for a given stats type
 func GetStatsLabels(statsType string) []string {
-	switch strings.ToLower(statsType) {
-	case "passing":
-		return []string {
-			"name",
-			"att",
-			"cmp",
-			"yds",
-			"tds",
-			"ints",
-			"twopta",
-			"twoptm",
-		}
-	case "rushing":
-		return []string {
-			"name",
-			"att",
-			"yds",
-			"tds",
-			"lng",
-			"lngtd",
-			"twopta",
-			"twoptm",
-		}
-	case "receiving":
-		return []string {
-			"name",
-			"rec",
-			"yds",
-			"tds",
-			"lng",
-			"lngtd",
-			"twopta",
-			"twoptm",
-		}
-
+	category, ok := GetStatCategory(statsType)
+	if !ok {
+		return nil
 	}
 
-	return nil
+	return append([]string{"name"}, category.GetFeedLabels()...)
 }
 
-func NewPassingStats(stats map[string]interface{}) PassingStats {
-	att, okAtt := stats["att"].(float64)
-	cmp, okCmp := stats["cmp"].(float64)
-	yds, okYds := stats["yds"].(float64)
-	tds, okTds := stats["tds"].(float64)
-	ints, okInts := stats["ints"].(float64)
-	twopta, okTwoPta := stats["twopta"].(float64)
-	twoptm, okTwoPtm := stats["twoptm"].(float64)
+// Return a summary of the given game stats with all the stat lines added together
+func NewPlayerStatsSummary(playerStats []PlayerStats) PlayerStatsSummary {
+	summary := PlayerStatsSummary{}
+
+	for _, gameStats := range playerStats {
+		// Use the most recent name and team for the player
+		summary.PlayerId = gameStats.PlayerId
+		summary.Name = gameStats.Name
+		summary.TeamAbbr = gameStats.TeamAbbr
+		summary.Games++
+		summary.StatLine = summary.StatLine.Add(gameStats.StatLine)
+	}
 
+	return summary
+}
 
-	// if can't type assert a field, return an empty Passing stats object
-	if !okAtt || !okCmp || !okYds || !okTds || !okInts || !okTwoPta || !okTwoPtm {
-		return PassingStats{}
+// Get the keys of every counting stat in a stat line, such as "rushing.yds".
+// Averages are left out since they can't be added up; they're in the derived metrics instead
+func GetStatKeys() []string {
+	var statKeys []string
+	for _, category := range statCategories {
+		for _, field := range category.Fields {
+			if field.Aggregate != AggregateRatio {
+				statKeys = append(statKeys, category.FeedKey + "." + field.Label)
+			}
+		}
 	}
+	return statKeys
+}
 
-	return PassingStats {
-		int(att),
-		int(cmp),
-		int(yds),
-		int(tds),
-		int(ints),
-		int(twopta),
-		int(twoptm),
+// Check if the key is for a stat in one of the stat categories
+func IsStatKey(statKey string) bool {
+	category, label, ok := splitStatKey(statKey)
+	if !ok {
+		return false
 	}
+
+	_, ok = category.GetField(label)
+	return ok
 }
 
-func NewRushingStats(stats map[string]interface{}) RushingStats {
-	att, okAtt := stats["att"].(float64)
-	yds, okYds := stats["yds"].(float64)
-	tds, okTds := stats["tds"].(float64)
-	lng, okLng := stats["lng"].(float64)
-	lngtd, okLngtd := stats["lngtd"].(float64)
-	twopta, okTwoPta := stats["twopta"].(float64)
-	twoptm, okTwoPtm := stats["twoptm"].(float64)
+// Get the value of a stat from the line given its key, such as "rushing.yds"
+func (line StatLine) GetStat(statKey string) (float64, bool) {
+	category, label, ok := splitStatKey(statKey)
+	if !ok {
+		return 0, false
+	}
+
+	value, ok := line[category.FeedKey][label]
+	return value, ok
+}
 
+// Get the value of a stat in a category, or 0 if the line doesn't have it or it's unknown
+func (line StatLine) Get(statsType string, label string) float64 {
+	return line[statsType][label]
+}
 
-	// if can't type assert a field, return an empty Rushing stats object
-	if !okAtt || !okYds || !okTds || !okLng || !okLngtd || !okTwoPta || !okTwoPtm {
-		return RushingStats{}
+// Add two stat lines together using each field's aggregate rule
+func (line StatLine) Add(other StatLine) StatLine {
+	total := make(StatLine)
+	for _, category := range statCategories {
+		total[category.FeedKey] = category.add(line[category.FeedKey], other[category.FeedKey])
 	}
+	return total
+}
 
-	return RushingStats {
-		int(att),
-		int(yds),
-		int(tds),
-		int(lng),
-		int(lngtd),
-		int(twopta),
-		int(twoptm),
+// Split a stat key such as "rushing.yds" into its category and field label
+func splitStatKey(statKey string) (StatCategory, string, bool) {
+	keyParts := strings.SplitN(strings.ToLower(statKey), ".", 2)
+	if len(keyParts) != 2 {
+		return StatCategory{}, "", false
 	}
+
+	category, ok := GetStatCategory(keyParts[0])
+	return category, keyParts[1], ok
 }
 
-func NewReceivingStats(stats map[string]interface{}) ReceivingStats {
-	rec, okRec := stats["rec"].(float64)
-	yds, okYds := stats["yds"].(float64)
-	tds, okTds := stats["tds"].(float64)
-	lng, okLng := stats["lng"].(float64)
-	lngtd, okLngtd := stats["lngtd"].(float64)
-	twopta, okTwoPta := stats["twopta"].(float64)
-	twoptm, okTwoPtm := stats["twoptm"].(float64)
+// Marshal the value to json, then add each stat category in the line under its json key.
+// Unknown stats are written as null
+func marshalWithStatLine(value interface{}, line StatLine) ([]byte, error) {
+	fields := make(map[string]interface{})
+	for _, category := range statCategories {
+		categoryJson := make(map[string]*float64)
+		for _, field := range category.Fields {
+			categoryJson[field.Label] = nil
+			if fieldValue, ok := line[category.FeedKey][field.Label]; ok {
+				categoryJson[field.Label] = &fieldValue
+			}
+		}
+		fields[category.JsonKey] = categoryJson
+	}
+
+	return marshalWithFields(value, fields)
+}
 
+// Marshal the value to a json object, then add the given fields to it
+func marshalWithFields(value interface{}, fields map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
 
-	// if can't type assert a field, return an empty Rushing stats object
-	if !okRec || !okYds || !okTds || !okLng || !okLngtd || !okTwoPta || !okTwoPtm {
-		return ReceivingStats{}
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(data, &object)
+	if err != nil {
+		return nil, err
 	}
 
-	return ReceivingStats {
-		int(rec),
-		int(yds),
-		int(tds),
-		int(lng),
-		int(lngtd),
-		int(twopta),
-		int(twoptm),
+	for key, fieldValue := range fields {
+		fieldData, err := json.Marshal(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		object[key] = fieldData
 	}
+
+	return json.Marshal(object)
 }
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPlayerStatsMarshalJSON(t *testing.T) {
+	playerStats := PlayerStats{
+		PlayerId: 1,
+		Name: "T.Brady",
+		StatLine: StatLine{"passing": {"att": 35, "cmp": 0}},
+	}
+
+	data, err := json.Marshal(playerStats)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	var object map[string]interface{}
+	json.Unmarshal(data, &object)
+	if object["name"] != "T.Brady" {
+		t.Errorf("got name %v, expected T.Brady", object["name"])
+	}
+
+	passing, ok := object["passingStats"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("got passingStats %v, expected an object", object["passingStats"])
+	}
+	if passing["att"] != 35.0 || passing["cmp"] != 0.0 {
+		t.Errorf("got att %v and cmp %v, expected the known 35 and 0", passing["att"], passing["cmp"])
+	}
+	if value, ok := passing["yds"]; !ok || value != nil {
+		t.Errorf("got yds %v, expected null", value)
+	}
+
+	// A category without any stats has every field as null
+	rushing, ok := object["rushingStats"].(map[string]interface{})
+	if !ok || len(rushing) != 7 {
+		t.Fatalf("got rushingStats %v, expected its 7 fields", object["rushingStats"])
+	}
+	for label, value := range rushing {
+		if value != nil {
+			t.Errorf("got rushing %v %v, expected null", label, value)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A team's stats for one game
+type TeamGameStats struct {
+	TeamAbbr string `json:"teamAbbr"`
+	GameDate time.Time `json:"gameDate"`
+	GameContext
+	Stats TeamStats `json:"stats"`
+}
+
+// Team totals from the team section of the feed. Stats that weren't in the feed are nil, so they're null
+// rather than 0
+type TeamStats struct {
+	FirstDowns *int `json:"totfd"`
+	TotalYards *int `json:"totyds"`
+	PassingYards *int `json:"pyds"`
+	RushingYards *int `json:"ryds"`
+	Penalties *int `json:"pen"`
+	PenaltyYards *int `json:"penyds"`
+	Turnovers *int `json:"trnovr"`
+	// Time of possession in seconds
+	TimeOfPossession *int `json:"top"`
+}
+
+// A team's stats and record over a set of games
+type TeamStatsSummary struct {
+	TeamAbbr string `json:"teamAbbr"`
+	Games int `json:"games"`
+	Wins int `json:"wins"`
+	Losses int `json:"losses"`
+	Ties int `json:"ties"`
+	PointsFor int `json:"pointsFor"`
+	PointsAgainst int `json:"pointsAgainst"`
+	Totals TeamStats `json:"totals"`
+	// Per game averages keyed by stat label, including points for and against
+	Averages map[string]float64 `json:"averages"`
+}
+
+// Return the team stats label set
+func GetTeamStatsLabels() []string {
+	return []string {
+		"totfd",
+		"totyds",
+		"pyds",
+		"ryds",
+		"pen",
+		"penyds",
+		"trnovr",
+		"top",
+	}
+}
+
+// Read a team's stats from the feed. A stat that's missing or can't be read is left unknown
+func NewTeamStats(stats map[string]interface{}) TeamStats {
+	return TeamStats {
+		FirstDowns: getFeedInt(stats, "totfd"),
+		TotalYards: getFeedInt(stats, "totyds"),
+		PassingYards: getFeedInt(stats, "pyds"),
+		RushingYards: getFeedInt(stats, "ryds"),
+		Penalties: getFeedInt(stats, "pen"),
+		PenaltyYards: getFeedInt(stats, "penyds"),
+		Turnovers: getFeedInt(stats, "trnovr"),
+		TimeOfPossession: getFeedClockSeconds(stats, "top"),
+	}
+}
+
+// Get a number from the feed, or nil if it's missing
+func getFeedInt(stats map[string]interface{}, label string) *int {
+	value, ok := stats[label].(float64)
+	if !ok {
+		return nil
+	}
+	intValue := int(value)
+	return &intValue
+}
+
+// Get a game clock such as "32:15" from the feed in seconds, or nil if it's missing
+func getFeedClockSeconds(stats map[string]interface{}, label string) *int {
+	clock, ok := stats[label].(string)
+	if !ok || len(strings.Split(clock, ":")) != 2 {
+		return nil
+	}
+	seconds := ParseClockSeconds(clock)
+	return &seconds
+}
+
+// Summarise each team's games, ordered by team
+func NewTeamStatsSummaries(teamGames []TeamGameStats) []TeamStatsSummary {
+	gamesByTeam := make(map[string][]TeamGameStats)
+	var teamAbbrs []string
+
+	for _, teamGame := range teamGames {
+		if _, exists := gamesByTeam[teamGame.TeamAbbr]; !exists {
+			teamAbbrs = append(teamAbbrs, teamGame.TeamAbbr)
+		}
+		gamesByTeam[teamGame.TeamAbbr] = append(gamesByTeam[teamGame.TeamAbbr], teamGame)
+	}
+
+	sort.Strings(teamAbbrs)
+	summaries := []TeamStatsSummary{}
+	for _, teamAbbr := range teamAbbrs {
+		summaries = append(summaries, NewTeamStatsSummary(gamesByTeam[teamAbbr]))
+	}
+	return summaries
+}
+
+// Add up a team's stats and record over the given games
+func NewTeamStatsSummary(teamGames []TeamGameStats) TeamStatsSummary {
+	summary := TeamStatsSummary {
+		Averages: make(map[string]float64),
+	}
+	knownGames := make(map[string]int)
+
+	for _, teamGame := range teamGames {
+		summary.TeamAbbr = teamGame.TeamAbbr
+		summary.Games++
+		summary.PointsFor += teamGame.TeamScore
+		summary.PointsAgainst += teamGame.OpponentScore
+		summary.Totals = summary.Totals.add(teamGame.Stats)
+		for label := range teamGame.Stats.getStatValues() {
+			knownGames[label]++
+		}
+
+		switch teamGame.Result {
+		case "W":
+			summary.Wins++
+		case "L":
+			summary.Losses++
+		case "T":
+			summary.Ties++
+		}
+	}
+
+	if summary.Games == 0 {
+		return summary
+	}
+
+	// A stat is averaged over the games it's known for
+	for label, value := range summary.Totals.getStatValues() {
+		summary.Averages[label] = roundToTenth(float64(value) / float64(knownGames[label]))
+	}
+	summary.Averages["pointsFor"] = roundToTenth(float64(summary.PointsFor) / float64(summary.Games))
+	summary.Averages["pointsAgainst"] = roundToTenth(float64(summary.PointsAgainst) / float64(summary.Games))
+
+	return summary
+}
+
+// Add two teams' stats together. A stat known in only one keeps that value, and stays unknown if it's in neither
+func (stats TeamStats) add(other TeamStats) TeamStats {
+	return TeamStats {
+		addNullableInts(stats.FirstDowns, other.FirstDowns),
+		addNullableInts(stats.TotalYards, other.TotalYards),
+		addNullableInts(stats.PassingYards, other.PassingYards),
+		addNullableInts(stats.RushingYards, other.RushingYards),
+		addNullableInts(stats.Penalties, other.Penalties),
+		addNullableInts(stats.PenaltyYards, other.PenaltyYards),
+		addNullableInts(stats.Turnovers, other.Turnovers),
+		addNullableInts(stats.TimeOfPossession, other.TimeOfPossession),
+	}
+}
+
+func addNullableInts(value *int, other *int) *int {
+	if value == nil {
+		return other
+	}
+	if other == nil {
+		return value
+	}
+	total := *value + *other
+	return &total
+}
+
+// Get the values of the known team stats keyed by their labels
+func (stats TeamStats) getStatValues() map[string]int {
+	values := make(map[string]int)
+	for label, value := range map[string]*int {
+		"totfd": stats.FirstDowns,
+		"totyds": stats.TotalYards,
+		"pyds": stats.PassingYards,
+		"ryds": stats.RushingYards,
+		"pen": stats.Penalties,
+		"penyds": stats.PenaltyYards,
+		"trnovr": stats.Turnovers,
+		"top": stats.TimeOfPossession,
+	} {
+		if value != nil {
+			values[label] = *value
+		}
+	}
+	return values
+}
+
+// Parse a game clock such as "32:15" into seconds
+func ParseClockSeconds(clock string) int {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 2 {
+		return 0
+	}
+
+	minutes, errMinutes := strconv.Atoi(parts[0])
+	seconds, errSeconds := strconv.Atoi(parts[1])
+	if errMinutes != nil || errSeconds != nil {
+		return 0
+	}
+	return minutes * 60 + seconds
+}
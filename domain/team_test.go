@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewTeamStats(t *testing.T) {
+	stats := NewTeamStats(map[string]interface{}{
+		"totfd": 22.0,
+		"totyds": 389.0,
+		"pyds": 277.0,
+		"ryds": 112.0,
+		"trnovr": 1.0,
+		"top": "32:15",
+	})
+
+	data, _ := json.Marshal(stats)
+	expected := `{"totfd":22,"totyds":389,"pyds":277,"ryds":112,"pen":null,"penyds":null,"trnovr":1,"top":1935}`
+	if string(data) != expected {
+		t.Errorf("got %v, expected %v", string(data), expected)
+	}
+
+	if stats := NewTeamStats(map[string]interface{}{"top": "--"}); stats.TimeOfPossession != nil {
+		t.Errorf("got time of possession %v for an unreadable clock, expected nil", *stats.TimeOfPossession)
+	}
+}
+
+func TestNewTeamStatsSummary(t *testing.T) {
+	teamGames := []TeamGameStats{
+		{
+			TeamAbbr: "NE",
+			GameContext: GameContext{Result: "W", TeamScore: 27, OpponentScore: 20},
+			Stats: NewTeamStats(map[string]interface{}{"totyds": 400.0, "pen": 5.0}),
+		},
+		{
+			TeamAbbr: "NE",
+			GameContext: GameContext{Result: "L", TeamScore: 10, OpponentScore: 17},
+			Stats: NewTeamStats(map[string]interface{}{"totyds": 300.0}),
+		},
+	}
+
+	summary := NewTeamStatsSummary(teamGames)
+	if summary.Games != 2 || summary.Wins != 1 || summary.Losses != 1 {
+		t.Errorf("got %v games, %v wins and %v losses, expected 2, 1 and 1", summary.Games, summary.Wins,
+			summary.Losses)
+	}
+
+	if summary.Totals.TotalYards == nil || *summary.Totals.TotalYards != 700 {
+		t.Errorf("got total yards %v, expected 700", summary.Totals.TotalYards)
+	}
+	if summary.Totals.FirstDowns != nil {
+		t.Errorf("got first downs %v, expected nil as no game has them", *summary.Totals.FirstDowns)
+	}
+
+	// Penalties are only known for one game, so they're averaged over that game
+	expectedAverages := map[string]float64{"totyds": 350, "pen": 5, "pointsFor": 18.5, "pointsAgainst": 18.5}
+	if !reflect.DeepEqual(summary.Averages, expectedAverages) {
+		t.Errorf("got averages %v, expected %v", summary.Averages, expectedAverages)
+	}
+}
+
+func TestParseClockSeconds(t *testing.T) {
+	tests := []struct {
+		clock string
+		expected int
+	}{
+		{"32:15", 1935},
+		{"00:45", 45},
+		{"7:03", 423},
+		{"", 0},
+		{"32", 0},
+		{"a:15", 0},
+	}
+
+	for _, test := range tests {
+		if seconds := ParseClockSeconds(test.clock); seconds != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.clock, seconds, test.expected)
+		}
+	}
+}
+
+func TestNewTeamStatsSummaries(t *testing.T) {
+	game := NewGame("2018090900", time.Date(2018, time.September, 9, 0, 0, 0, 0, time.UTC), "NE", "HOU", 27, 20)
+	tie := NewGame("2018091600", time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC), "HOU", "NYG", 17, 17)
+	newTeamGame := func(game Game, teamAbbr string, firstDowns float64) TeamGameStats {
+		return TeamGameStats{TeamAbbr: teamAbbr, GameDate: game.GameDate, GameContext: NewGameContext(game, teamAbbr),
+			Stats: NewTeamStats(map[string]interface{}{"totfd": firstDowns})}
+	}
+
+	summaries := NewTeamStatsSummaries([]TeamGameStats{
+		newTeamGame(game, "NE", 24),
+		newTeamGame(game, "HOU", 18),
+		newTeamGame(tie, "NYG", 15),
+		newTeamGame(tie, "HOU", 21),
+	})
+
+	tests := []struct {
+		teamAbbr string
+		record Record
+		pointsFor int
+		pointsAgainst int
+		firstDowns int
+	}{
+		{"HOU", Record{0, 1, 1}, 37, 44, 39},
+		{"NE", Record{1, 0, 0}, 27, 20, 24},
+		{"NYG", Record{0, 0, 1}, 17, 17, 15},
+	}
+
+	if len(summaries) != len(tests) {
+		t.Fatalf("got %v summaries, expected %v", len(summaries), len(tests))
+	}
+	for i, test := range tests {
+		summary := summaries[i]
+		record := Record{summary.Wins, summary.Losses, summary.Ties}
+		if summary.TeamAbbr != test.teamAbbr || record != test.record || summary.PointsFor != test.pointsFor ||
+			summary.PointsAgainst != test.pointsAgainst || *summary.Totals.FirstDowns != test.firstDowns {
+			t.Errorf("summary %v: got %v %+v, %v-%v and %v first downs, expected %v %+v, %v-%v and %v", i,
+				summary.TeamAbbr, record, summary.PointsFor, summary.PointsAgainst, *summary.Totals.FirstDowns,
+				test.teamAbbr, test.record, test.pointsFor, test.pointsAgainst, test.firstDowns)
+		}
+	}
+}
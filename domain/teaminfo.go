@@ -0,0 +1,81 @@
+package domain
+
+import "strings"
+
+// A team's name and where it plays in the league
+type TeamInfo struct {
+	Abbr string `json:"abbr"`
+	Name string `json:"name"`
+	Conference string `json:"conference"`
+	Division string `json:"division"`
+}
+
+// Every team, by the abbreviations used in the feed. Teams that have moved keep their old abbreviations
+// so older games still line up
+var teamInfos = []TeamInfo {
+	{"BUF", "Buffalo Bills", "AFC", "East"},
+	{"MIA", "Miami Dolphins", "AFC", "East"},
+	{"NE", "New England Patriots", "AFC", "East"},
+	{"NYJ", "New York Jets", "AFC", "East"},
+	{"BAL", "Baltimore Ravens", "AFC", "North"},
+	{"CIN", "Cincinnati Bengals", "AFC", "North"},
+	{"CLE", "Cleveland Browns", "AFC", "North"},
+	{"PIT", "Pittsburgh Steelers", "AFC", "North"},
+	{"HOU", "Houston Texans", "AFC", "South"},
+	{"IND", "Indianapolis Colts", "AFC", "South"},
+	{"JAX", "Jacksonville Jaguars", "AFC", "South"},
+	{"JAC", "Jacksonville Jaguars", "AFC", "South"},
+	{"TEN", "Tennessee Titans", "AFC", "South"},
+	{"DEN", "Denver Broncos", "AFC", "West"},
+	{"KC", "Kansas City Chiefs", "AFC", "West"},
+	{"LAC", "Los Angeles Chargers", "AFC", "West"},
+	{"SD", "San Diego Chargers", "AFC", "West"},
+	{"LV", "Las Vegas Raiders", "AFC", "West"},
+	{"OAK", "Oakland Raiders", "AFC", "West"},
+	{"DAL", "Dallas Cowboys", "NFC", "East"},
+	{"NYG", "New York Giants", "NFC", "East"},
+	{"PHI", "Philadelphia Eagles", "NFC", "East"},
+	{"WAS", "Washington Redskins", "NFC", "East"},
+	{"CHI", "Chicago Bears", "NFC", "North"},
+	{"DET", "Detroit Lions", "NFC", "North"},
+	{"GB", "Green Bay Packers", "NFC", "North"},
+	{"MIN", "Minnesota Vikings", "NFC", "North"},
+	{"ATL", "Atlanta Falcons", "NFC", "South"},
+	{"CAR", "Carolina Panthers", "NFC", "South"},
+	{"NO", "New Orleans Saints", "NFC", "South"},
+	{"TB", "Tampa Bay Buccaneers", "NFC", "South"},
+	{"ARI", "Arizona Cardinals", "NFC", "West"},
+	{"LA", "Los Angeles Rams", "NFC", "West"},
+	{"STL", "St. Louis Rams", "NFC", "West"},
+	{"SF", "San Francisco 49ers", "NFC", "West"},
+	{"SEA", "Seattle Seahawks", "NFC", "West"},
+}
+
+// Get every team's name, conference and division
+func GetTeamInfos() []TeamInfo {
+	return teamInfos
+}
+
+// Get a team's name, conference and division by its abbreviation
+func GetTeamInfo(teamAbbr string) (TeamInfo, bool) {
+	for _, teamInfo := range teamInfos {
+		if teamInfo.Abbr == strings.ToUpper(teamAbbr) {
+			return teamInfo, true
+		}
+	}
+	return TeamInfo{}, false
+}
+
+// Are the teams in the same division?
+func IsDivisionGame(teamAbbr string, otherAbbr string) bool {
+	teamInfo, ok := GetTeamInfo(teamAbbr)
+	otherInfo, otherOk := GetTeamInfo(otherAbbr)
+	return ok && otherOk && teamInfo.Conference == otherInfo.Conference && teamInfo.Division == otherInfo.Division
+}
+
+// Are the teams in the same conference?
+func IsConferenceGame(teamAbbr string, otherAbbr string) bool {
+	teamInfo, ok := GetTeamInfo(teamAbbr)
+	otherInfo, otherOk := GetTeamInfo(otherAbbr)
+	return ok && otherOk && teamInfo.Conference == otherInfo.Conference
+}
@@ -0,0 +1,320 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Plays where a team's win probability is at least this high are in garbage time
+const garbageTimeWinProbability = 0.95
+
+// The model is only fitted once there are this many plays to fit it on
+const minWinProbabilitySamples = 1000
+
+// Used until the model has been fitted on enough plays
+var defaultWinProbabilityWeights = []float64{0, 0.6, 0.5, 0.3, 0.1, 0}
+
+// The chance of the team with the ball winning from the score, time left, field position, down and distance.
+// It's a logistic regression fitted on whether the team with the ball won in every ingested play
+type WinProbabilityModel struct {
+	weights []float64
+	expectedPointsModel ExpectedPointsModel
+}
+
+// The state of a game at the start of a play, from the point of view of the team with the ball
+type winProbabilityState struct {
+	scoreDifference int
+	secondsLeft int
+	isHome bool
+	expectedPoints float64
+}
+
+// The home team's chance of winning at the start of a play
+type WinProbabilityPoint struct {
+	PlayId int `json:"playId"`
+	Quarter int `json:"quarter"`
+	Time string `json:"time"`
+	// The team with the ball
+	TeamAbbr string `json:"teamAbbr"`
+	Down int `json:"down"`
+	YardsToGo int `json:"yardsToGo"`
+	FieldPosition int `json:"fieldPosition"`
+	HomeScore int `json:"homeScore"`
+	AwayScore int `json:"awayScore"`
+	HomeWinProbability float64 `json:"homeWinProbability"`
+	// Is either team at least 95% to win?
+	IsGarbageTime bool `json:"isGarbageTime"`
+	Description string `json:"description"`
+}
+
+// A game with the home team's win probability at each play from scrimmage
+type GameWinProbability struct {
+	Game
+	Plays []WinProbabilityPoint `json:"plays"`
+}
+
+// Fit the win probability model from the plays in the given games and their scoring plays. Only regular season
+// and playoff games finished by now are used, since unfinished games have no outcome yet and preseason games
+// aren't played to win
+func NewWinProbabilityModel(games []Game, plays []Play, scoringPlays []ScoringPlay,
+	expectedPointsModel ExpectedPointsModel, now time.Time) WinProbabilityModel {
+	model := WinProbabilityModel {
+		weights: defaultWinProbabilityWeights,
+		expectedPointsModel: expectedPointsModel,
+	}
+
+	gamesByKey := make(map[string]Game)
+	for _, game := range games {
+		if game.Week >= 1 && game.IsFinished(now) {
+			gamesByKey[game.GameKey] = game
+		}
+	}
+
+	scoringPlaysByGame := groupScoringPlaysByGame(scoringPlays)
+	var features [][]float64
+	var outcomes []float64
+	for _, gamePlays := range groupPlaysByGame(plays) {
+		game, ok := gamesByKey[gamePlays[0].GameKey]
+		if !ok {
+			continue
+		}
+
+		for _, point := range getWinProbabilityPoints(game, gamePlays, scoringPlaysByGame[game.GameKey]) {
+			state := model.getState(game, point)
+			features = append(features, state.getFeatures())
+			outcomes = append(outcomes, getWinOutcome(game, point.TeamAbbr))
+		}
+	}
+
+	if len(features) >= minWinProbabilitySamples {
+		model.weights = fitLogisticRegression(features, outcomes)
+	}
+	return model
+}
+
+// Get the home team's win probability at each play from scrimmage in the game
+func NewGameWinProbability(model WinProbabilityModel, game Game, plays []Play,
+	scoringPlays []ScoringPlay) GameWinProbability {
+	gameWinProbability := GameWinProbability {
+		Game: game,
+		Plays: []WinProbabilityPoint{},
+	}
+
+	for _, gamePlays := range groupPlaysByGame(plays) {
+		if gamePlays[0].GameKey != game.GameKey {
+			continue
+		}
+
+		for _, point := range getWinProbabilityPoints(game, gamePlays, scoringPlays) {
+			winProbability := model.getState(game, point).getWinProbability(model.weights)
+			if point.TeamAbbr != game.HomeAbbr {
+				winProbability = 1 - winProbability
+			}
+
+			point.HomeWinProbability = math.Round(winProbability * 1000) / 1000
+			point.IsGarbageTime = winProbability >= garbageTimeWinProbability ||
+				winProbability <= 1 - garbageTimeWinProbability
+			gameWinProbability.Plays = append(gameWinProbability.Plays, point)
+		}
+	}
+
+	return gameWinProbability
+}
+
+// Get a point for each play from scrimmage in a game's plays, with the score before the play
+func getWinProbabilityPoints(game Game, gamePlays []Play, scoringPlays []ScoringPlay) []WinProbabilityPoint {
+	var gameScoringPlays []ScoringPlay
+	for _, scoringPlay := range scoringPlays {
+		if scoringPlay.GameKey == game.GameKey {
+			gameScoringPlays = append(gameScoringPlays, scoringPlay)
+		}
+	}
+	sort.Slice(gameScoringPlays, func(i, j int) bool {
+		return gameScoringPlays[i].PlayId < gameScoringPlays[j].PlayId
+	})
+
+	var points []WinProbabilityPoint
+	homeScore := 0
+	awayScore := 0
+	scoringIndex := 0
+	for _, play := range gamePlays {
+		// Add the scores from before this play
+		for scoringIndex < len(gameScoringPlays) && gameScoringPlays[scoringIndex].PlayId < play.PlayId {
+			if gameScoringPlays[scoringIndex].TeamAbbr == game.HomeAbbr {
+				homeScore += gameScoringPlays[scoringIndex].Points
+			} else {
+				awayScore += gameScoringPlays[scoringIndex].Points
+			}
+			scoringIndex++
+		}
+
+		if !play.IsScrimmage() || !game.HasTeam(play.TeamAbbr) {
+			continue
+		}
+
+		points = append(points, WinProbabilityPoint {
+			PlayId: play.PlayId,
+			Quarter: play.Quarter,
+			Time: play.Time,
+			TeamAbbr: play.TeamAbbr,
+			Down: play.Down,
+			YardsToGo: play.YardsToGo,
+			FieldPosition: play.FieldPosition,
+			HomeScore: homeScore,
+			AwayScore: awayScore,
+			Description: play.Description,
+		})
+	}
+	return points
+}
+
+// Get the state of the game at a point from the point of view of the team with the ball
+func (model WinProbabilityModel) getState(game Game, point WinProbabilityPoint) winProbabilityState {
+	isHome := point.TeamAbbr == game.HomeAbbr
+	scoreDifference := point.HomeScore - point.AwayScore
+	if !isHome {
+		scoreDifference = -scoreDifference
+	}
+
+	return winProbabilityState {
+		scoreDifference: scoreDifference,
+		secondsLeft: getSecondsLeft(point.Quarter, point.Time),
+		isHome: isHome,
+		expectedPoints: model.expectedPointsModel.GetExpectedPoints(point.Down, point.YardsToGo, point.FieldPosition),
+	}
+}
+
+// Get the model's features for the state. The score difference counts for more as time runs out
+func (state winProbabilityState) getFeatures() []float64 {
+	timeLeft := float64(state.secondsLeft) / 3600
+	home := -1.0
+	if state.isHome {
+		home = 1
+	}
+
+	return []float64 {
+		1,
+		float64(state.scoreDifference) / 10,
+		float64(state.scoreDifference) / 10 / math.Sqrt(timeLeft + 0.01),
+		state.expectedPoints / 7,
+		home,
+		timeLeft,
+	}
+}
+
+func (state winProbabilityState) getWinProbability(weights []float64) float64 {
+	return getLogisticValue(weights, state.getFeatures())
+}
+
+// Get the seconds left in regulation at a quarter and game clock such as "12:34". Overtime has none left
+func getSecondsLeft(quarter int, clock string) int {
+	if quarter > 4 || quarter < 1 {
+		return 0
+	}
+	return (4 - quarter) * 900 + ParseClockSeconds(clock)
+}
+
+// Group the scoring plays by their game key
+func groupScoringPlaysByGame(scoringPlays []ScoringPlay) map[string][]ScoringPlay {
+	scoringPlaysByGame := make(map[string][]ScoringPlay)
+	for _, scoringPlay := range scoringPlays {
+		scoringPlaysByGame[scoringPlay.GameKey] = append(scoringPlaysByGame[scoringPlay.GameKey], scoringPlay)
+	}
+	return scoringPlaysByGame
+}
+
+// Get whether the team won the game: 1 for a win, 0 for a loss and a half for a tie
+func getWinOutcome(game Game, teamAbbr string) float64 {
+	switch game.GetResult(teamAbbr) {
+	case "W":
+		return 1
+	case "L":
+		return 0
+	}
+	return 0.5
+}
+
+// Fit the weights of a logistic regression with Newton's method, which settles in a few iterations
+// whatever the scale of the features
+func fitLogisticRegression(features [][]float64, outcomes []float64) []float64 {
+	const iterations = 15
+	// Keeps the system solvable when a feature barely varies
+	const ridge = 0.001
+
+	featureCount := len(features[0])
+	weights := make([]float64, featureCount)
+	for iteration := 0; iteration < iterations; iteration++ {
+		gradient := make([]float64, featureCount)
+		hessian := make([][]float64, featureCount)
+		for j := range hessian {
+			hessian[j] = make([]float64, featureCount)
+			hessian[j][j] = ridge
+		}
+
+		for i, featureValues := range features {
+			probability := getLogisticValue(weights, featureValues)
+			difference := outcomes[i] - probability
+			variance := probability * (1 - probability)
+			for j, value := range featureValues {
+				gradient[j] += difference * value
+				for k, otherValue := range featureValues {
+					hessian[j][k] += variance * value * otherValue
+				}
+			}
+		}
+
+		step, ok := solveLinearSystem(hessian, gradient)
+		if !ok {
+			break
+		}
+		for j := range weights {
+			weights[j] += step[j]
+		}
+	}
+	return weights
+}
+
+// Solve a x = b with gaussian elimination
+func solveLinearSystem(a [][]float64, b []float64) ([]float64, bool) {
+	size := len(b)
+	for column := 0; column < size; column++ {
+		pivot := column
+		for row := column + 1; row < size; row++ {
+			if math.Abs(a[row][column]) > math.Abs(a[pivot][column]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][column]) < 1e-12 {
+			return nil, false
+		}
+		a[column], a[pivot] = a[pivot], a[column]
+		b[column], b[pivot] = b[pivot], b[column]
+
+		for row := column + 1; row < size; row++ {
+			factor := a[row][column] / a[column][column]
+			for k := column; k < size; k++ {
+				a[row][k] -= factor * a[column][k]
+			}
+			b[row] -= factor * b[column]
+		}
+	}
+
+	x := make([]float64, size)
+	for row := size - 1; row >= 0; row-- {
+		total := b[row]
+		for k := row + 1; k < size; k++ {
+			total -= a[row][k] * x[k]
+		}
+		x[row] = total / a[row][row]
+	}
+	return x, true
+}
+
+func getLogisticValue(weights []float64, features []float64) float64 {
+	total := 0.0
+	for i, weight := range weights {
+		total += weight * features[i]
+	}
+	return 1 / (1 + math.Exp(-total))
+}
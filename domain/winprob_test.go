@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFitLogisticRegressionRecoversWeights(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	expected := []float64{0.5, 2, -1}
+
+	var features [][]float64
+	var outcomes []float64
+	for i := 0; i < 5000; i++ {
+		featureValues := []float64{1, random.NormFloat64(), random.NormFloat64()}
+		outcome := 0.0
+		if random.Float64() < getLogisticValue(expected, featureValues) {
+			outcome = 1
+		}
+		features = append(features, featureValues)
+		outcomes = append(outcomes, outcome)
+	}
+
+	weights := fitLogisticRegression(features, outcomes)
+	for i := range expected {
+		if math.Abs(weights[i] - expected[i]) > 0.2 {
+			t.Errorf("weight %v = %v, expected about %v", i, weights[i], expected[i])
+		}
+	}
+}
+
+func TestGetSecondsLeft(t *testing.T) {
+	tests := []struct {
+		quarter int
+		clock string
+		expected int
+	}{
+		{1, "15:00", 3600},
+		{2, "02:00", 1920},
+		{4, "00:30", 30},
+		{5, "10:00", 0},
+	}
+
+	for _, test := range tests {
+		actual := getSecondsLeft(test.quarter, test.clock)
+		if actual != test.expected {
+			t.Errorf("getSecondsLeft(%v, %v) = %v, expected %v", test.quarter, test.clock, actual, test.expected)
+		}
+	}
+}
+
+func TestNewWinProbabilityModelOnlyFitsFinishedSeasonGames(t *testing.T) {
+	now := time.Date(2018, time.October, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		game Game
+		isFitted bool
+	}{
+		{"finished", NewGame("2018093000", time.Date(2018, time.September, 30, 0, 0, 0, 0, time.UTC), "NE", "MIA", 38, 7), true},
+		{"preseason", NewGame("2018083000", time.Date(2018, time.August, 30, 0, 0, 0, 0, time.UTC), "NE", "NYG", 38, 7), false},
+		{"not finished", NewGame("2018101000", now, "NE", "KC", 7, 0), false},
+	}
+
+	for _, test := range tests {
+		var plays []Play
+		for playId := 1; playId <= minWinProbabilitySamples; playId++ {
+			plays = append(plays, Play {
+				GameKey: test.game.GameKey,
+				PlayId: playId,
+				TeamAbbr: []string{"NE", test.game.AwayAbbr}[playId % 2],
+				Quarter: 1 + playId * 4 / (minWinProbabilitySamples + 1),
+				Time: "07:30",
+				Down: 1 + playId % 4,
+				YardsToGo: 10,
+				FieldPosition: playId % 100,
+			})
+		}
+
+		model := NewWinProbabilityModel([]Game{test.game}, plays, nil, ExpectedPointsModel{}, now)
+		isFitted := !equalWeights(model.weights, defaultWinProbabilityWeights)
+		if isFitted != test.isFitted {
+			t.Errorf("%v game: fitted = %v, expected %v", test.name, isFitted, test.isFitted)
+		}
+	}
+}
+
+func equalWeights(weights []float64, other []float64) bool {
+	for i := range weights {
+		if weights[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -6,23 +6,71 @@ import (
 	"time"
 	"./repository"
 	"./update"
+	"./domain"
 	"gopkg.in/matryer/respond.v1"
 	"fmt"
+	"strconv"
+	"encoding/json"
+	"strings"
+	"sync"
 )
 
 var (
 	playerRepository = repository.NewPlayerSqlRepository()
+	scoringRepository = repository.NewScoringSqlRepository()
+	teamRepository = repository.NewTeamSqlRepository()
+	driveRepository = repository.NewDriveSqlRepository()
+	playRepository = repository.NewPlaySqlRepository()
+	gameRepository = repository.NewGameSqlRepository()
+
+	// The expected points and win probability models, fitted from the ingested plays in the background.
+	// Only read and written through the play model functions, which hold the mutex. Until the first fit finishes,
+	// expected points come from field position alone and win probability from the default weights
+	expectedPointsModel domain.ExpectedPointsModel
+	winProbabilityModel = domain.NewWinProbabilityModel(nil, nil, nil, domain.ExpectedPointsModel{}, time.Now())
+	playModelsMutex sync.Mutex
 )
 
 func main() {
 	// Initialize ticker for update data process
 	go startUpdateDataProcess()
+	// Fit the play models now and once a day after
+	go startFitPlayModelsProcess()
 
 	router := mux.NewRouter()
 
 	/** API Routes **/
 	router.HandleFunc("/api/search/player/{searchText}", getPlayersBySearchText)
 	router.HandleFunc("/api/player/{playerId}", getPlayerStatsByPlayerId)
+	router.HandleFunc("/api/player/{playerId}/summary", getPlayerStatsSummaryByPlayerId)
+	router.HandleFunc("/api/player/{playerId}/splits", getPlayerSplitsByPlayerId)
+	router.HandleFunc("/api/player/{playerId}/position", updatePlayerPosition).Methods("PUT")
+	router.HandleFunc("/api/player/{playerId}/position", deletePlayerPosition).Methods("DELETE")
+	router.HandleFunc("/api/players/roster", importRoster).Methods("POST")
+	router.HandleFunc("/api/leaders", getLeaders)
+	router.HandleFunc("/api/compare", comparePlayers)
+	router.HandleFunc("/api/teams", getTeams)
+	router.HandleFunc("/api/teams/{abbr}", getTeamByAbbr)
+	router.HandleFunc("/api/teams/{abbr}/games", getTeamGamesByAbbr)
+	router.HandleFunc("/api/teams/{abbr}/drives", getTeamDriveEfficiencyByAbbr)
+	router.HandleFunc("/api/drives/efficiency", getDriveEfficiencies)
+	router.HandleFunc("/api/standings", getStandings)
+	router.HandleFunc("/api/standings/playoff-picture", getPlayoffPicture)
+	router.HandleFunc("/api/games/{gameKey}", getGameDetailByGameKey)
+	router.HandleFunc("/api/games/{gameKey}/drives", getDrivesByGameKey)
+	router.HandleFunc("/api/games/{gameKey}/plays", getPlaysByGameKey)
+	router.HandleFunc("/api/games/{gameKey}/winprob", getWinProbabilityByGameKey)
+	router.HandleFunc("/api/epa/players", getPlayerEpas)
+	router.HandleFunc("/api/epa/teams", getTeamEpas)
+	router.HandleFunc("/api/plays/search", searchPlays)
+	router.HandleFunc("/api/ratings", getEloRatings)
+	router.HandleFunc("/api/predictions", getPredictions)
+	router.HandleFunc("/api/matchups", getMatchups)
+	router.HandleFunc("/api/scoring/rulesets", getScoringRulesets).Methods("GET")
+	router.HandleFunc("/api/scoring/rulesets", createScoringRuleset).Methods("POST")
+	router.HandleFunc("/api/scoring/rulesets/{name}", getScoringRuleset).Methods("GET")
+	router.HandleFunc("/api/scoring/rulesets/{name}", updateScoringRuleset).Methods("PUT")
+	router.HandleFunc("/api/scoring/rulesets/{name}", deleteScoringRuleset).Methods("DELETE")
 
 
 
@@ -41,23 +89,843 @@ func startUpdateDataProcess() {
 	}
 }
 
+// Fit the play models from every ingested play, then refit them every 24 hours
+func startFitPlayModelsProcess() {
+	fitPlayModels()
+	ticker := time.NewTicker(24 * time.Hour)
+	for tick := range ticker.C {
+		fmt.Println("Fit play models process started at: ", tick)
+		fitPlayModels()
+	}
+}
+
 // get all players whose first or last names start with the search text
 func getPlayersBySearchText(w http.ResponseWriter, r *http.Request) {
 	enableCors(&w)
 	searchText := mux.Vars(r)["searchText"]
 	players := playerRepository.GetPlayersBySearchText(searchText)
+
+	position := strings.ToUpper(r.URL.Query().Get("position"))
+	if len(position) > 0 {
+		var playersAtPosition []domain.Player
+		for _, player := range players {
+			if player.Position == position {
+				playersAtPosition = append(playersAtPosition, player)
+			}
+		}
+		players = playersAtPosition
+	}
 	respond.With(w, r, http.StatusOK, players)
 }
 
+// set a player's position by hand from a body like {"position": "TE"}, over their roster and inferred positions
+func updatePlayerPosition(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	playerId, err := strconv.Atoi(mux.Vars(r)["playerId"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "playerId must be a number")
+		return
+	}
+
+	var body struct {
+		Position string `json:"position"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid position: " + err.Error())
+		return
+	}
+
+	position, ok := domain.NormalizePosition(body.Position)
+	if !ok {
+		respondWithError(w, r, http.StatusBadRequest, "position must be an abbreviation such as QB or TE")
+		return
+	}
+
+	playerRepository.SavePositionOverride(playerId, position)
+	respond.With(w, r, http.StatusOK, map[string]interface{} {
+		"playerId": playerId,
+		"position": position,
+	})
+}
+
+// clear a player's position override, going back to their roster or inferred position
+func deletePlayerPosition(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	playerId, err := strconv.Atoi(mux.Vars(r)["playerId"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "playerId must be a number")
+		return
+	}
+
+	playerRepository.SavePositionOverride(playerId, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// import player positions, full names and aliases from a roster, as a json list or a csv with a text/csv content type
+func importRoster(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	var entries []domain.RosterEntry
+	var err error
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		entries, err = domain.ParseRosterCsv(r.Body)
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&entries)
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "invalid roster: " + err.Error())
+		return
+	}
+
+	entries, err = domain.ValidateRoster(entries)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playerRepository.SaveRoster(entries)
+	respond.With(w, r, http.StatusOK, map[string]int{"imported": len(entries)})
+}
+
 // get player data for a particular player id
 func getPlayerStatsByPlayerId(w http.ResponseWriter, r *http.Request) {
 	enableCors(&w)
-	playerId := mux.Vars(r)["playerId"]
+	playerId, err := strconv.Atoi(mux.Vars(r)["playerId"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "playerId must be a number")
+		return
+	}
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	playerData := playerRepository.GetPlayerStatsByPlayerId(playerId)
+
+	for i := range playerData {
+		if isQueryFlagSet(r, "metrics") {
+			metrics := domain.NewMetrics(playerData[i].StatLine)
+			playerData[i].Metrics = &metrics
+		}
+		if isScored {
+			points := ruleset.Score(playerData[i].StatLine)
+			playerData[i].FantasyPoints = &points
+		}
+	}
+
 	respond.With(w, r, http.StatusOK, playerData)
 
 }
 
+// get the aggregated stats over all games for a particular player id
+func getPlayerStatsSummaryByPlayerId(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	playerId, err := strconv.Atoi(mux.Vars(r)["playerId"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "playerId must be a number")
+		return
+	}
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playerData := playerRepository.GetPlayerStatsByPlayerId(playerId)
+	summary := domain.NewPlayerStatsSummary(playerData)
+
+	if isQueryFlagSet(r, "metrics") {
+		metrics := domain.NewMetrics(summary.StatLine)
+		summary.Metrics = &metrics
+	}
+
+	if isScored {
+		points := ruleset.ScoreGames(playerData)
+		summary.FantasyPoints = &points
+	}
+
+	respond.With(w, r, http.StatusOK, summary)
+}
+
+// get a player's stats and derived metrics split by home/away, opponent, month, result or day of week
+func getPlayerSplitsByPlayerId(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	playerId, err := strconv.Atoi(mux.Vars(r)["playerId"])
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "playerId must be a number")
+		return
+	}
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var splitRuleset *domain.ScoringRuleset
+	if isScored {
+		splitRuleset = &ruleset
+	}
+
+	by := r.URL.Query().Get("by")
+	var splits []domain.Split
+	if domain.IsPlaySplit(by) {
+		// Play splits can't be scored since bonuses and some scored stats are only in game totals
+		if isScored {
+			respondWithError(w, r, http.StatusBadRequest, "scoring isn't available for the " + by + " split")
+			return
+		}
+		splits, err = domain.NewPlaySplits(playerId, playRepository.GetPlaysByPlayerId(playerId), by)
+	} else {
+		playerData := playerRepository.GetPlayerStatsByPlayerId(playerId)
+		splits, err = domain.NewSplits(playerData, by, splitRuleset)
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, splits)
+}
+
+// get players ranked by a stat, metric or fantasy score over a date range
+func getLeaders(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	query := r.URL.Query()
+
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	options := domain.LeaderboardOptions {
+		Stat: query.Get("stat"),
+		Limit: 25,
+		IsAscending: strings.EqualFold(query.Get("order"), "asc"),
+	}
+
+	if len(options.Stat) == 0 {
+		respondWithError(w, r, http.StatusBadRequest, "stat is required")
+		return
+	}
+
+	if len(query.Get("limit")) > 0 {
+		options.Limit, err = strconv.Atoi(query.Get("limit"))
+	}
+	if err == nil && len(query.Get("minAttempts")) > 0 {
+		options.MinAttempts, err = strconv.Atoi(query.Get("minAttempts"))
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "limit and minAttempts must be numbers")
+		return
+	}
+
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !isScored {
+		ruleset, _ = domain.GetBuiltInScoringRuleset("standard")
+	}
+	options.ScoringRuleset = ruleset
+
+	options.Position = strings.ToUpper(query.Get("position"))
+	options.KnownPositions = playerRepository.GetKnownPositions()
+
+	// A single game is ranked with ?game= instead of dates
+	var playerStats []domain.PlayerStats
+	gameKey := query.Get("game")
+	if len(gameKey) > 0 {
+		if _, ok := gameRepository.GetGameByKey(gameKey); !ok {
+			respondWithError(w, r, http.StatusNotFound, "game not found: " + gameKey)
+			return
+		}
+		playerStats = playerRepository.GetPlayerStatsByGameKey(gameKey)
+	} else {
+		playerStats = playerRepository.GetPlayerStatsForDateRange(from, to)
+	}
+
+	leaders, err := domain.NewLeaderboard(playerStats, options)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, leaders)
+}
+
+// get several players' totals, averages, metrics and weekly games side by side
+func comparePlayers(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+
+	var playerIds []int
+	for _, playerIdText := range strings.Split(r.URL.Query().Get("players"), ",") {
+		playerId, err := strconv.Atoi(strings.TrimSpace(playerIdText))
+		if err != nil {
+			respondWithError(w, r, http.StatusBadRequest, "players must be a comma separated list of player ids")
+			return
+		}
+		playerIds = append(playerIds, playerId)
+	}
+
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var comparisonRuleset *domain.ScoringRuleset
+	if isScored {
+		comparisonRuleset = &ruleset
+	}
+
+	playerStats := playerRepository.GetPlayerStatsByPlayerIdsForDateRange(playerIds, from, to)
+	comparison := domain.NewPlayerComparison(playerIds, playerStats, comparisonRuleset)
+	respond.With(w, r, http.StatusOK, comparison)
+}
+
+// get the stats and record of every team over a date range
+func getTeams(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	teamGames := teamRepository.GetTeamGameStatsForDateRange(from, to)
+	respond.With(w, r, http.StatusOK, domain.NewTeamStatsSummaries(teamGames))
+}
+
+// get a team's stats, per game averages and record over a date range
+func getTeamByAbbr(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	teamAbbr := strings.ToUpper(mux.Vars(r)["abbr"])
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	teamGames := teamRepository.GetTeamGameStatsByTeam(teamAbbr, from, to)
+	if len(teamGames) == 0 {
+		respondWithError(w, r, http.StatusNotFound, "no games found for team: " + teamAbbr)
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, domain.NewTeamStatsSummary(teamGames))
+}
+
+// get a team's stats for each game over a date range
+func getTeamGamesByAbbr(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	teamAbbr := strings.ToUpper(mux.Vars(r)["abbr"])
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	teamGames := teamRepository.GetTeamGameStatsByTeam(teamAbbr, from, to)
+	respond.With(w, r, http.StatusOK, teamGames)
+}
+
+// get each team's points per drive and share of drives ending in a score or turnover over a date range
+func getDriveEfficiencies(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drives := driveRepository.GetDrivesForDateRange(from, to)
+	respond.With(w, r, http.StatusOK, domain.NewDriveEfficiencies(drives))
+}
+
+// get a team's drive efficiency over a date range
+func getTeamDriveEfficiencyByAbbr(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	teamAbbr := strings.ToUpper(mux.Vars(r)["abbr"])
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	drives := driveRepository.GetDrivesByTeam(teamAbbr, from, to)
+	if len(drives) == 0 {
+		respondWithError(w, r, http.StatusNotFound, "no drives found for team: " + teamAbbr)
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, domain.NewDriveEfficiency(drives))
+}
+
+// get each division's standings for a season, the current season by default
+func getStandings(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	season, err := getSeasonForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, domain.NewStandings(gameRepository.GetGamesBySeason(season), time.Now()))
+}
+
+// get each conference's playoff seeds for a season, the current season by default
+func getPlayoffPicture(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	season, err := getSeasonForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, domain.NewPlayoffPicture(season, gameRepository.GetGamesBySeason(season),
+		time.Now()))
+}
+
+// get a game's result with its quarter by quarter linescore and scoring plays
+func getGameDetailByGameKey(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	gameKey := mux.Vars(r)["gameKey"]
+
+	game, ok := gameRepository.GetGameByKey(gameKey)
+	if !ok {
+		respondWithError(w, r, http.StatusNotFound, "game not found: " + gameKey)
+		return
+	}
+
+	linescores := gameRepository.GetLinescoresByGameKey(gameKey)
+	scoringPlays := gameRepository.GetScoringPlaysByGameKey(gameKey)
+	respond.With(w, r, http.StatusOK, domain.NewGameDetail(game, linescores, scoringPlays))
+}
+
+// get the drives in a game
+func getDrivesByGameKey(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	gameKey := mux.Vars(r)["gameKey"]
+
+	drives := driveRepository.GetDrivesByGameKey(gameKey)
+	if len(drives) == 0 {
+		respondWithError(w, r, http.StatusNotFound, "no drives found for game: " + gameKey)
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, drives)
+}
+
+// get the plays from scrimmage in a game with the expected points added by each
+func getPlaysByGameKey(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	gameKey := mux.Vars(r)["gameKey"]
+
+	plays := playRepository.GetPlaysByGameKey(gameKey)
+	if len(plays) == 0 {
+		respondWithError(w, r, http.StatusNotFound, "no plays found for game: " + gameKey)
+		return
+	}
+
+	scoringPlays := gameRepository.GetScoringPlaysByGameKey(gameKey)
+	respond.With(w, r, http.StatusOK, domain.NewPlayEpas(getExpectedPointsModel(), plays, scoringPlays))
+}
+
+// get the home team's win probability at each play from scrimmage in a game
+func getWinProbabilityByGameKey(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	gameKey := mux.Vars(r)["gameKey"]
+
+	game, ok := gameRepository.GetGameByKey(gameKey)
+	if !ok {
+		respondWithError(w, r, http.StatusNotFound, "game not found: " + gameKey)
+		return
+	}
+
+	plays := playRepository.GetPlaysByGameKey(gameKey)
+	scoringPlays := gameRepository.GetScoringPlaysByGameKey(gameKey)
+	respond.With(w, r, http.StatusOK, domain.NewGameWinProbability(getWinProbabilityModel(), game, plays, scoringPlays))
+}
+
+// get each passer, rusher and receiver's expected points added over a date range, from most to least
+func getPlayerEpas(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	query := r.URL.Query()
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 25
+	minPlays := 0
+	if len(query.Get("limit")) > 0 {
+		limit, err = strconv.Atoi(query.Get("limit"))
+	}
+	if err == nil && len(query.Get("minPlays")) > 0 {
+		minPlays, err = strconv.Atoi(query.Get("minPlays"))
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "limit and minPlays must be numbers")
+		return
+	}
+
+	playerEpas := []domain.PlayerEpa{}
+	for _, playerEpa := range domain.NewPlayerEpas(getPlayEpasForDateRange(from, to)) {
+		if playerEpa.Plays >= minPlays {
+			playerEpas = append(playerEpas, playerEpa)
+		}
+	}
+	if limit > 0 && len(playerEpas) > limit {
+		playerEpas = playerEpas[:limit]
+	}
+
+	respond.With(w, r, http.StatusOK, playerEpas)
+}
+
+// get each team's expected points added on offense and allowed on defense over a date range
+func getTeamEpas(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	from, to, err := getDateRangeForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, domain.NewTeamEpas(getPlayEpasForDateRange(from, to)))
+}
+
+// search play descriptions, optionally filtered to a player, team, season or the red zone
+func searchPlays(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	query := r.URL.Query()
+	options := domain.PlaySearchOptions {
+		Text: query.Get("q"),
+		TeamAbbr: strings.ToUpper(query.Get("team")),
+		IsRedZone: isQueryFlagSet(r, "redZone"),
+		Limit: 100,
+	}
+
+	var err error
+	if len(query.Get("player")) > 0 {
+		options.PlayerId, err = strconv.Atoi(query.Get("player"))
+	}
+	if err == nil && len(query.Get("season")) > 0 {
+		options.Season, err = strconv.Atoi(query.Get("season"))
+	}
+	if err == nil && len(query.Get("limit")) > 0 {
+		options.Limit, err = strconv.Atoi(query.Get("limit"))
+	}
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, "player, season and limit must be numbers")
+		return
+	}
+	if options.Limit <= 0 {
+		respondWithError(w, r, http.StatusBadRequest, "limit must be more than 0")
+		return
+	}
+
+	respond.With(w, r, http.StatusOK, playRepository.SearchPlays(options))
+}
+
+// Get the expected points added by each play in games between the from and to dates
+func getPlayEpasForDateRange(from time.Time, to time.Time) []domain.PlayEpa {
+	plays := playRepository.GetPlaysForDateRange(from, to)
+	scoringPlays := gameRepository.GetScoringPlaysForDateRange(from, to)
+	return domain.NewPlayEpas(getExpectedPointsModel(), plays, scoringPlays)
+}
+
+// Get the latest fitted expected points model
+func getExpectedPointsModel() domain.ExpectedPointsModel {
+	playModelsMutex.Lock()
+	defer playModelsMutex.Unlock()
+	return expectedPointsModel
+}
+
+// Get the latest fitted win probability model
+func getWinProbabilityModel() domain.WinProbabilityModel {
+	playModelsMutex.Lock()
+	defer playModelsMutex.Unlock()
+	return winProbabilityModel
+}
+
+// Fit the expected points and win probability models from every ingested play. The win probability model is
+// fitted with the new expected points model, and both are swapped in together once they're done
+func fitPlayModels() {
+	plays := playRepository.GetAllPlays()
+	scoringPlays := gameRepository.GetAllScoringPlays()
+	newExpectedPointsModel := domain.NewExpectedPointsModel(plays, scoringPlays)
+	newWinProbabilityModel := domain.NewWinProbabilityModel(gameRepository.GetAllGames(), plays, scoringPlays,
+		newExpectedPointsModel, time.Now())
+
+	playModelsMutex.Lock()
+	defer playModelsMutex.Unlock()
+	expectedPointsModel = newExpectedPointsModel
+	winProbabilityModel = newWinProbabilityModel
+}
+
+// get every team's Elo rating with its game by game history, or one team's with ?team=
+func getEloRatings(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	settings, err := getEloSettingsForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	teamRatings := domain.NewEloRatings(gameRepository.GetAllGames(), settings, time.Now()).GetTeamRatings()
+	teamAbbr := strings.ToUpper(r.URL.Query().Get("team"))
+	if len(teamAbbr) == 0 {
+		respond.With(w, r, http.StatusOK, teamRatings)
+		return
+	}
+
+	for _, teamRating := range teamRatings {
+		if teamRating.TeamAbbr == teamAbbr {
+			respond.With(w, r, http.StatusOK, teamRating)
+			return
+		}
+	}
+	respondWithError(w, r, http.StatusNotFound, "no rating found for team: " + teamAbbr)
+}
+
+// get the predicted result of the season's games that haven't finished, or of any matchup with ?home= and ?away=
+func getPredictions(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	settings, err := getEloSettingsForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	season, err := getSeasonForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	ratings := domain.NewEloRatings(gameRepository.GetAllGames(), settings, now)
+
+	query := r.URL.Query()
+	homeAbbr := strings.ToUpper(query.Get("home"))
+	awayAbbr := strings.ToUpper(query.Get("away"))
+	if len(homeAbbr) > 0 || len(awayAbbr) > 0 {
+		_, okHome := domain.GetTeamInfo(homeAbbr)
+		_, okAway := domain.GetTeamInfo(awayAbbr)
+		if !okHome || !okAway || homeAbbr == awayAbbr {
+			respondWithError(w, r, http.StatusBadRequest, "home and away must be two different teams")
+			return
+		}
+
+		respond.With(w, r, http.StatusOK, ratings.Predict(domain.Game {
+			Season: season,
+			HomeAbbr: homeAbbr,
+			AwayAbbr: awayAbbr,
+		}))
+		return
+	}
+
+	// The feed only has games that have started, so the games to predict come from the schedule
+	schedule := gameRepository.GetScheduleBySeason(season)
+	if len(schedule) == 0 {
+		respondWithError(w, r, http.StatusNotFound, fmt.Sprintf("no schedule has been loaded for the %v season", season))
+		return
+	}
+
+	predictions := []domain.GamePrediction{}
+	for _, game := range domain.GetRemainingGames(schedule, now) {
+		predictions = append(predictions, ratings.Predict(game))
+	}
+	respond.With(w, r, http.StatusOK, predictions)
+}
+
+// get the fantasy points and yards each defense allowed to a position in a season, with each team's remaining schedule
+func getMatchups(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	season, err := getSeasonForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	position, ok := domain.ParsePosition(r.URL.Query().Get("position"))
+	if !ok {
+		respondWithError(w, r, http.StatusBadRequest, "position must be QB, RB, WR or TE")
+		return
+	}
+
+	ruleset, isScored, err := getScoringRulesetForRequest(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !isScored {
+		ruleset, _ = domain.GetBuiltInScoringRuleset("standard")
+	}
+
+	knownPositions := playerRepository.GetKnownPositions()
+	if position == domain.PositionTightEnd && !domain.HasKnownPosition(knownPositions, position) {
+		respondWithError(w, r, http.StatusBadRequest,
+			"tight ends can't be told apart from stats, so import a roster or set positions before asking for TE")
+		return
+	}
+
+	// The feed only has games that have started, so the remaining games come from the schedule
+	schedule := gameRepository.GetScheduleBySeason(season)
+	if len(schedule) == 0 {
+		respondWithError(w, r, http.StatusNotFound, fmt.Sprintf("no schedule has been loaded for the %v season", season))
+		return
+	}
+
+	now := time.Now()
+	from, to := domain.GetSeasonDateRange(season)
+	playerStats := playerRepository.GetPlayerStatsForDateRange(from, to)
+	respond.With(w, r, http.StatusOK, domain.NewMatchupTable(season, position, gameRepository.GetGamesBySeason(season),
+		domain.GetRemainingGames(schedule, now), playerStats, knownPositions, ruleset, now))
+}
+
+// Get the Elo settings, overriding the defaults with ?homeField= and ?regression=
+func getEloSettingsForRequest(r *http.Request) (domain.EloSettings, error) {
+	settings := domain.DefaultEloSettings
+	query := r.URL.Query()
+
+	var err error
+	if len(query.Get("homeField")) > 0 {
+		settings.HomeFieldAdvantage, err = strconv.ParseFloat(query.Get("homeField"), 64)
+	}
+	if err == nil && len(query.Get("regression")) > 0 {
+		settings.SeasonRegression, err = strconv.ParseFloat(query.Get("regression"), 64)
+	}
+	if err != nil || settings.SeasonRegression < 0 || settings.SeasonRegression > 1 {
+		return settings, fmt.Errorf("homeField must be a number of rating points and regression a fraction from 0 to 1")
+	}
+	return settings, nil
+}
+
+// get the built in and custom scoring rulesets
+func getScoringRulesets(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	rulesets := domain.GetBuiltInScoringRulesets()
+	rulesets = append(rulesets, scoringRepository.GetScoringRulesets()...)
+	respond.With(w, r, http.StatusOK, rulesets)
+}
+
+// get a built in or custom scoring ruleset by name
+func getScoringRuleset(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	name := mux.Vars(r)["name"]
+	ruleset, ok := findScoringRuleset(name)
+	if !ok {
+		respondWithError(w, r, http.StatusNotFound, "scoring ruleset not found: " + name)
+		return
+	}
+	respond.With(w, r, http.StatusOK, ruleset)
+}
+
+// create a custom scoring ruleset from the request body
+func createScoringRuleset(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	ruleset, err := decodeScoringRuleset(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, exists := findScoringRuleset(ruleset.Name); exists {
+		respondWithError(w, r, http.StatusConflict, "scoring ruleset already exists: " + ruleset.Name)
+		return
+	}
+
+	scoringRepository.SaveScoringRuleset(ruleset)
+	respond.With(w, r, http.StatusCreated, ruleset)
+}
+
+// replace a custom scoring ruleset with the request body
+func updateScoringRuleset(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	name := mux.Vars(r)["name"]
+	ruleset, err := decodeScoringRuleset(r)
+	if err != nil {
+		respondWithError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, isBuiltIn := domain.GetBuiltInScoringRuleset(name); isBuiltIn {
+		respondWithError(w, r, http.StatusForbidden, "built in scoring rulesets can't be changed")
+		return
+	}
+
+	// The name in the url always wins over the one in the body
+	ruleset.Name = name
+	scoringRepository.SaveScoringRuleset(ruleset)
+	respond.With(w, r, http.StatusOK, ruleset)
+}
+
+// delete a custom scoring ruleset
+func deleteScoringRuleset(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	name := mux.Vars(r)["name"]
+
+	if _, isBuiltIn := domain.GetBuiltInScoringRuleset(name); isBuiltIn {
+		respondWithError(w, r, http.StatusForbidden, "built in scoring rulesets can't be deleted")
+		return
+	}
+
+	if _, exists := scoringRepository.GetScoringRuleset(name); !exists {
+		respondWithError(w, r, http.StatusNotFound, "scoring ruleset not found: " + name)
+		return
+	}
+
+	scoringRepository.DeleteScoringRuleset(name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Read a custom scoring ruleset from the request body and check that it's valid
+func decodeScoringRuleset(r *http.Request) (domain.ScoringRuleset, error) {
+	var ruleset domain.ScoringRuleset
+	err := json.NewDecoder(r.Body).Decode(&ruleset)
+	if err != nil {
+		return ruleset, err
+	}
+
+	ruleset.IsBuiltIn = false
+	if ruleset.Bonuses == nil {
+		ruleset.Bonuses = []domain.ScoringBonus{}
+	}
+	return ruleset, ruleset.Validate()
+}
+
+// Find a built in or custom scoring ruleset by name
+func findScoringRuleset(name string) (domain.ScoringRuleset, bool) {
+	ruleset, ok := domain.GetBuiltInScoringRuleset(name)
+	if ok {
+		return ruleset, true
+	}
+	return scoringRepository.GetScoringRuleset(name)
+}
+
+// Get the ruleset named by ?scoring= on the request, if one was given
+func getScoringRulesetForRequest(r *http.Request) (domain.ScoringRuleset, bool, error) {
+	name := r.URL.Query().Get("scoring")
+	if len(name) == 0 {
+		return domain.ScoringRuleset{}, false, nil
+	}
+
+	ruleset, ok := findScoringRuleset(name)
+	if !ok {
+		return ruleset, false, fmt.Errorf("scoring ruleset not found: %v", name)
+	}
+	return ruleset, true, nil
+}
 
 // Get the server information
 func getServer(router http.Handler) http.Server {
@@ -74,4 +942,76 @@ func getServer(router http.Handler) http.Server {
 
 func enableCors(w *http.ResponseWriter) {
 	(*w).Header().Set("Access-Control-Allow-Origin", "*")
+}
+
+// Respond with an error message and status code
+func respondWithError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	respond.With(w, r, status, map[string]string {
+		"error": message,
+	})
+}
+
+// Get the dates a request covers from its from/to, date or season/week query parameters.
+// Defaults to the current season
+func getDateRangeForRequest(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	dateLayout := "2006-01-02"
+
+	if len(query.Get("from")) > 0 || len(query.Get("to")) > 0 {
+		from, fromErr := time.Parse(dateLayout, query.Get("from"))
+		to, toErr := time.Parse(dateLayout, query.Get("to"))
+		if fromErr != nil || toErr != nil {
+			return from, to, fmt.Errorf("from and to must both be dates formatted as %v", dateLayout)
+		}
+		return from, to, nil
+	}
+
+	if len(query.Get("date")) > 0 {
+		date, err := time.Parse(dateLayout, query.Get("date"))
+		if err != nil {
+			return date, date, fmt.Errorf("date must be formatted as %v", dateLayout)
+		}
+		return date, date, nil
+	}
+
+	season := domain.GetSeason(time.Now())
+	if len(query.Get("season")) > 0 {
+		var err error
+		season, err = strconv.Atoi(query.Get("season"))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("season must be a year")
+		}
+	}
+
+	if len(query.Get("week")) > 0 {
+		week, err := strconv.Atoi(query.Get("week"))
+		if err != nil || week < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("week must be a number from 1")
+		}
+		from, to := domain.GetWeekDateRange(season, week)
+		return from, to, nil
+	}
+
+	from, to := domain.GetSeasonDateRange(season)
+	return from, to, nil
+}
+
+// Get the season in the request's season parameter, or the current season if it isn't given
+func getSeasonForRequest(r *http.Request) (int, error) {
+	seasonParam := r.URL.Query().Get("season")
+	if len(seasonParam) == 0 {
+		return domain.GetSeason(time.Now()), nil
+	}
+
+	season, err := strconv.Atoi(seasonParam)
+	if err != nil {
+		return 0, fmt.Errorf("season must be a year")
+	}
+	return season, nil
+}
+
+// Check if a boolean query parameter such as ?metrics=true was set on the request
+func isQueryFlagSet(r *http.Request, name string) bool {
+	value, err := strconv.ParseBool(r.URL.Query().Get(name))
+	return err == nil && value
 }
\ No newline at end of file
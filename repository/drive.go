@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"../domain"
+	"../utils"
+)
+
+type DriveSqlRepository struct {
+	config Configuration
+}
+
+func NewDriveSqlRepository() DriveSqlRepository {
+	repo := DriveSqlRepository{}
+	repo.config = newDefaultConfiguration()
+	return repo
+}
+
+// Get the drives in a game, in the order they happened
+func (repo DriveSqlRepository) GetDrivesByGameKey(gameKey string) []domain.Drive {
+	return repo.queryDrives(fmt.Sprintf("where d.gamekey = %v ", quoteSqlString(gameKey)))
+}
+
+// Get every team's drives in games between the from and to dates, inclusive
+func (repo DriveSqlRepository) GetDrivesForDateRange(from time.Time, to time.Time) []domain.Drive {
+	return repo.queryDrives(fmt.Sprintf("where g.gamedate between '%v' and '%v' ",
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Get a team's drives in games between the from and to dates, inclusive
+func (repo DriveSqlRepository) GetDrivesByTeam(teamAbbr string, from time.Time, to time.Time) []domain.Drive {
+	return repo.queryDrives(fmt.Sprintf("where d.teamAbbr = %v and g.gamedate between '%v' and '%v' ",
+		quoteSqlString(teamAbbr),
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Save the drives of a game
+func (repo DriveSqlRepository) SaveDrives(drives []domain.Drive) {
+	tvpSaveQuery := ""
+	for _, drive := range drives {
+		newQueryLine := fmt.Sprintf("\nSELECT '%v', %v, '%v', %v, '%v', '%v', %v, '%v', '%v', %v, %v, %v, %v, %v, %v, %v, %v",
+			drive.GameKey,
+			drive.DriveNum,
+			drive.TeamAbbr,
+			drive.StartQuarter,
+			drive.StartTime,
+			drive.StartYardLine,
+			drive.EndQuarter,
+			drive.EndTime,
+			drive.EndYardLine,
+			drive.Plays,
+			drive.Yards,
+			drive.FirstDowns,
+			drive.PenaltyYards,
+			drive.PossessionTime,
+			formatBitForQuery(drive.IsRedZone),
+			quoteSqlString(drive.Result),
+			drive.Points)
+
+		if len(tvpSaveQuery) == 0 {
+			tvpSaveQuery += "\nDECLARE @r DriveTvp\n"
+			tvpSaveQuery += fmt.Sprintf("INSERT INTO @r %v", newQueryLine)
+			continue
+		}
+		tvpSaveQuery += fmt.Sprintf(" UNION %v", newQueryLine)
+	}
+
+	if len(tvpSaveQuery) == 0 {
+		return
+	}
+	tvpSaveQuery += "\nexec SaveDrive @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, tvpSaveQuery)
+}
+
+// Get the drives matching the given where clause, ordered by game and drive number
+func (repo DriveSqlRepository) queryDrives(whereClause string) []domain.Drive {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := "select d.gamekey, d.drivenum, d.teamAbbr, d.startqtr, d.starttime, d.startyrdln, " +
+	"d.endqtr, d.endtime, d.endyrdln, d.numplays, d.ydsgained, d.fds, d.penyds, d.postime, " +
+	"d.redzone, d.result, d.points " +
+	"from Drive d " +
+	"join Game g " +
+	"on d.gamekey = g.gamekey " +
+	whereClause +
+	"order by g.gamedate, d.gamekey, d.drivenum"
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	drives := []domain.Drive{}
+	for rows.Next() {
+		var drive domain.Drive
+		rows.Scan(
+			&drive.GameKey,
+			&drive.DriveNum,
+			&drive.TeamAbbr,
+			&drive.StartQuarter,
+			&drive.StartTime,
+			&drive.StartYardLine,
+			&drive.EndQuarter,
+			&drive.EndTime,
+			&drive.EndYardLine,
+			&drive.Plays,
+			&drive.Yards,
+			&drive.FirstDowns,
+			&drive.PenaltyYards,
+			&drive.PossessionTime,
+			&drive.IsRedZone,
+			&drive.Result,
+			&drive.Points,
+		)
+		drives = append(drives, drive)
+	}
+
+	return drives
+}
+
+// Get the database connection
+func (repo DriveSqlRepository) getDbConn() *sql.DB {
+	return repo.config.getDbConn()
+}
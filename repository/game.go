@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"../domain"
+	"../utils"
+)
+
+type GameSqlRepository struct {
+	config Configuration
+}
+
+func NewGameSqlRepository() GameSqlRepository {
+	repo := GameSqlRepository{}
+	repo.config = newDefaultConfiguration()
+	return repo
+}
+
+// Get a game by its key
+func (repo GameSqlRepository) GetGameByKey(gameKey string) (domain.Game, bool) {
+	games := repo.queryGames(fmt.Sprintf("where gamekey = %v ", quoteSqlString(gameKey)))
+	if len(games) == 0 {
+		return domain.Game{}, false
+	}
+	return games[0], true
+}
+
+// Get the games in a season, including the preseason and playoffs
+func (repo GameSqlRepository) GetGamesBySeason(season int) []domain.Game {
+	return repo.queryGames(fmt.Sprintf("where season = %v ", season))
+}
+
+// Get every game that's been saved
+func (repo GameSqlRepository) GetAllGames() []domain.Game {
+	return repo.queryGames("")
+}
+
+// Get each team's points per quarter in a game
+func (repo GameSqlRepository) GetLinescoresByGameKey(gameKey string) []domain.TeamLinescore {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := fmt.Sprintf("select teamAbbr, qtr, points from GameLinescore " +
+		"where gamekey = %v order by teamAbbr, qtr", quoteSqlString(gameKey))
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	quarterPointsByTeam := make(map[string]map[int]int)
+	var teamAbbrs []string
+	for rows.Next() {
+		var teamAbbr string
+		var quarter, points int
+		rows.Scan(&teamAbbr, &quarter, &points)
+
+		if _, exists := quarterPointsByTeam[teamAbbr]; !exists {
+			quarterPointsByTeam[teamAbbr] = make(map[int]int)
+			teamAbbrs = append(teamAbbrs, teamAbbr)
+		}
+		quarterPointsByTeam[teamAbbr][quarter] = points
+	}
+
+	linescores := []domain.TeamLinescore{}
+	for _, teamAbbr := range teamAbbrs {
+		linescores = append(linescores, domain.NewTeamLinescore(gameKey, teamAbbr, quarterPointsByTeam[teamAbbr]))
+	}
+	return linescores
+}
+
+// Get the scoring plays in a game
+func (repo GameSqlRepository) GetScoringPlaysByGameKey(gameKey string) []domain.ScoringPlay {
+	return repo.queryScoringPlays(fmt.Sprintf("where sp.gamekey = %v ", quoteSqlString(gameKey)))
+}
+
+// Get the scoring plays in games between the from and to dates, inclusive
+func (repo GameSqlRepository) GetScoringPlaysForDateRange(from time.Time, to time.Time) []domain.ScoringPlay {
+	return repo.queryScoringPlays(fmt.Sprintf("where g.gamedate between '%v' and '%v' ",
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Get every scoring play that's been saved
+func (repo GameSqlRepository) GetAllScoringPlays() []domain.ScoringPlay {
+	return repo.queryScoringPlays("")
+}
+
+// Save each team's points per quarter in a game, with overtime saved as quarter 5
+func (repo GameSqlRepository) SaveLinescores(linescores []domain.TeamLinescore) {
+	tvpSaveQuery := ""
+	for _, linescore := range linescores {
+		quarterPoints := append(append([]int{}, linescore.Quarters...), linescore.Overtime)
+		for index, points := range quarterPoints {
+			newQueryLine := fmt.Sprintf("\nSELECT '%v', '%v', %v, %v",
+				linescore.GameKey,
+				linescore.TeamAbbr,
+				index + 1,
+				points)
+			tvpSaveQuery = addToTvpQuery(tvpSaveQuery, "GameLinescoreTvp", newQueryLine)
+		}
+	}
+
+	if len(tvpSaveQuery) == 0 {
+		return
+	}
+	tvpSaveQuery += "\nexec SaveGameLinescore @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, tvpSaveQuery)
+}
+
+// Save the scoring plays in a game
+func (repo GameSqlRepository) SaveScoringPlays(scoringPlays []domain.ScoringPlay) {
+	tvpSaveQuery := ""
+	for _, scoringPlay := range scoringPlays {
+		newQueryLine := fmt.Sprintf("\nSELECT '%v', %v, '%v', %v, '%v', %v, '%v', %v, %v",
+			scoringPlay.GameKey,
+			scoringPlay.PlayId,
+			scoringPlay.TeamAbbr,
+			scoringPlay.Quarter,
+			scoringPlay.Type,
+			quoteSqlString(scoringPlay.Description),
+			scoringPlay.ScorerKey,
+			quoteSqlString(scoringPlay.Scorer),
+			scoringPlay.Points)
+		tvpSaveQuery = addToTvpQuery(tvpSaveQuery, "ScoringPlayTvp", newQueryLine)
+	}
+
+	if len(tvpSaveQuery) == 0 {
+		return
+	}
+	tvpSaveQuery += "\nexec SaveScoringPlay @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, tvpSaveQuery)
+}
+
+// Get the league's schedule for a season, including the games that haven't been played
+func (repo GameSqlRepository) GetScheduleBySeason(season int) []domain.ScheduledGame {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := fmt.Sprintf("select gamekey, gamedate, homeAbbr, awayAbbr, homeScore, awayScore, status " +
+		"from ScheduledGame " +
+		"where season = %v " +
+		"order by gamedate, gamekey", season)
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	schedule := []domain.ScheduledGame{}
+	for rows.Next() {
+		var game domain.Game
+		var gameDate time.Time
+		var status string
+		rows.Scan(
+			&game.GameKey,
+			&gameDate,
+			&game.HomeAbbr,
+			&game.AwayAbbr,
+			&game.HomeScore,
+			&game.AwayScore,
+			&status,
+		)
+		schedule = append(schedule, domain.ScheduledGame {
+			Game: domain.NewGame(game.GameKey, gameDate, game.HomeAbbr, game.AwayAbbr, game.HomeScore, game.AwayScore),
+			Status: status,
+		})
+	}
+	return schedule
+}
+
+// Save games from the league's schedule, updating the status and scores of games already saved
+func (repo GameSqlRepository) SaveSchedule(schedule []domain.ScheduledGame) {
+	tvpSaveQuery := ""
+	for _, game := range schedule {
+		newQueryLine := fmt.Sprintf("\nSELECT '%v', '%v', %v, %v, '%v', '%v', %v, %v, %v",
+			game.GameKey,
+			formatDateForQuery(game.GameDate),
+			game.Season,
+			game.Week,
+			game.HomeAbbr,
+			game.AwayAbbr,
+			game.HomeScore,
+			game.AwayScore,
+			quoteSqlString(game.Status))
+		tvpSaveQuery = addToTvpQuery(tvpSaveQuery, "ScheduledGameTvp", newQueryLine)
+	}
+
+	if len(tvpSaveQuery) == 0 {
+		return
+	}
+	tvpSaveQuery += "\nexec SaveScheduledGame @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, tvpSaveQuery)
+}
+
+// Get the games matching the given where clause, ordered by date
+func (repo GameSqlRepository) queryGames(whereClause string) []domain.Game {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := "select gamekey, gamedate, homeAbbr, awayAbbr, homeScore, awayScore " +
+	"from Game " +
+	whereClause +
+	"order by gamedate, gamekey"
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	games := []domain.Game{}
+	for rows.Next() {
+		var game domain.Game
+		var gameDate time.Time
+		rows.Scan(
+			&game.GameKey,
+			&gameDate,
+			&game.HomeAbbr,
+			&game.AwayAbbr,
+			&game.HomeScore,
+			&game.AwayScore,
+		)
+		games = append(games, domain.NewGame(game.GameKey, gameDate, game.HomeAbbr, game.AwayAbbr,
+			game.HomeScore, game.AwayScore))
+	}
+	return games
+}
+
+// Get the scoring plays matching the given where clause, ordered by game and play
+func (repo GameSqlRepository) queryScoringPlays(whereClause string) []domain.ScoringPlay {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := "select sp.gamekey, sp.playid, sp.teamAbbr, sp.qtr, sp.type, sp.description, " +
+	"sp.scorerid, sp.scorer, sp.points " +
+	"from ScoringPlay sp " +
+	"join Game g " +
+	"on sp.gamekey = g.gamekey " +
+	whereClause +
+	"order by g.gamedate, sp.gamekey, sp.playid"
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	scoringPlays := []domain.ScoringPlay{}
+	for rows.Next() {
+		var scoringPlay domain.ScoringPlay
+		rows.Scan(
+			&scoringPlay.GameKey,
+			&scoringPlay.PlayId,
+			&scoringPlay.TeamAbbr,
+			&scoringPlay.Quarter,
+			&scoringPlay.Type,
+			&scoringPlay.Description,
+			&scoringPlay.ScorerKey,
+			&scoringPlay.Scorer,
+			&scoringPlay.Points,
+		)
+		scoringPlays = append(scoringPlays, scoringPlay)
+	}
+	return scoringPlays
+}
+
+// Get the database connection
+func (repo GameSqlRepository) getDbConn() *sql.DB {
+	return repo.config.getDbConn()
+}
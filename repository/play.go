@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"../domain"
+	"../utils"
+)
+
+type PlaySqlRepository struct {
+	config Configuration
+}
+
+func NewPlaySqlRepository() PlaySqlRepository {
+	repo := PlaySqlRepository{}
+	repo.config = newDefaultConfiguration()
+	return repo
+}
+
+// Get the plays in a game, in the order they happened
+func (repo PlaySqlRepository) GetPlaysByGameKey(gameKey string) []domain.Play {
+	return repo.queryPlays(fmt.Sprintf("where pl.gamekey = %v ", quoteSqlString(gameKey)), 0)
+}
+
+// Get every play in games between the from and to dates, inclusive
+func (repo PlaySqlRepository) GetPlaysForDateRange(from time.Time, to time.Time) []domain.Play {
+	return repo.queryPlays(fmt.Sprintf("where g.gamedate between '%v' and '%v' ",
+		formatDateForQuery(from),
+		formatDateForQuery(to)), 0)
+}
+
+// Get every play a player was involved in
+func (repo PlaySqlRepository) GetPlaysByPlayerId(playerId int) []domain.Play {
+	return repo.queryPlays("where " + getPlayerInPlayCondition(playerId) + " ", 0)
+}
+
+// Get every play that's been saved
+func (repo PlaySqlRepository) GetAllPlays() []domain.Play {
+	return repo.queryPlays("", 0)
+}
+
+// Get the plays matching the search options, most recent games first. The description text is matched
+// with the full-text index on the Play table
+func (repo PlaySqlRepository) SearchPlays(options domain.PlaySearchOptions) []domain.Play {
+	var conditions []string
+
+	if searchCondition := getFullTextSearchCondition(options.Text); len(searchCondition) > 0 {
+		conditions = append(conditions, fmt.Sprintf("contains(pl.description, %v)", quoteSqlString(searchCondition)))
+	}
+	if options.PlayerId > 0 {
+		conditions = append(conditions, getPlayerInPlayCondition(options.PlayerId))
+	}
+	if len(options.TeamAbbr) > 0 {
+		conditions = append(conditions, fmt.Sprintf("pl.teamAbbr = %v", quoteSqlString(options.TeamAbbr)))
+	}
+	if options.Season > 0 {
+		conditions = append(conditions, fmt.Sprintf("g.season = %v", options.Season))
+	}
+	if options.IsRedZone {
+		conditions = append(conditions, "pl.fieldpos >= 80")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "where " + strings.Join(conditions, " and ") + " "
+	}
+	return repo.queryPlays(whereClause, options.Limit)
+}
+
+// Save the plays of a game and the players involved in them
+func (repo PlaySqlRepository) SavePlays(plays []domain.Play) {
+	playTvpSaveQuery := ""
+	playPlayerTvpSaveQuery := ""
+
+	for _, play := range plays {
+		newQueryLine := fmt.Sprintf("\nSELECT '%v', %v, %v, '%v', %v, %v, %v, '%v', '%v', %v, %v, %v, %v",
+			play.GameKey,
+			play.PlayId,
+			play.DriveNum,
+			play.TeamAbbr,
+			play.Quarter,
+			play.Down,
+			play.YardsToGo,
+			play.Time,
+			play.YardLine,
+			play.FieldPosition,
+			play.Yards,
+			quoteSqlString(play.Description),
+			quoteSqlString(play.Note))
+		playTvpSaveQuery = addToTvpQuery(playTvpSaveQuery, "PlayTvp", newQueryLine)
+
+		for _, player := range play.Players {
+			newQueryLine := fmt.Sprintf("\nSELECT '%v', %v, '%v', %v, '%v', %v, %v",
+				play.GameKey,
+				play.PlayId,
+				player.PlayerKey,
+				quoteSqlString(player.Name),
+				player.TeamAbbr,
+				player.StatId,
+				player.Yards)
+			playPlayerTvpSaveQuery = addToTvpQuery(playPlayerTvpSaveQuery, "PlayPlayerTvp", newQueryLine)
+		}
+	}
+
+	if len(playTvpSaveQuery) == 0 {
+		return
+	}
+	playTvpSaveQuery += "\nexec SavePlay @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, playTvpSaveQuery)
+	if len(playPlayerTvpSaveQuery) > 0 {
+		playPlayerTvpSaveQuery += "\nexec SavePlayPlayer @records = @r"
+		executeModifyQuery(conn, playPlayerTvpSaveQuery)
+	}
+}
+
+// Get the plays matching the given where clause along with their players. A limit of 0 gets every play
+func (repo PlaySqlRepository) queryPlays(whereClause string, limit int) []domain.Play {
+	db := repo.getDbConn()
+	defer db.Close()
+
+	// The plays matching the filters, shared by the play and play player queries.
+	// Sql server only allows an order by in a subquery when it has a top
+	top := ""
+	orderBy := ""
+	if limit > 0 {
+		top = fmt.Sprintf("top (%v) ", limit)
+		orderBy = "order by g.gamedate desc, pl.gamekey, pl.playid"
+	}
+
+	matchingPlaysQuery := "select " + top + "pl.gamekey, pl.playid, g.gamedate, g.homeAbbr, g.awayAbbr " +
+	"from Play pl " +
+	"join Game g " +
+	"on pl.gamekey = g.gamekey " +
+	whereClause +
+	orderBy
+
+	query := "select pl.gamekey, pl.playid, pl.drivenum, pl.teamAbbr, pl.qtr, pl.down, pl.ydstogo, " +
+	"pl.time, pl.yrdln, pl.fieldpos, pl.ydsnet, pl.description, pl.note, m.homeAbbr, m.awayAbbr " +
+	"from Play pl " +
+	"join (" + matchingPlaysQuery + ") m " +
+	"on pl.gamekey = m.gamekey " +
+	"and pl.playid = m.playid " +
+	"order by m.gamedate desc, pl.gamekey, pl.playid"
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	plays := []domain.Play{}
+	playIndexes := make(map[string]int)
+	for rows.Next() {
+		var play domain.Play
+		var game domain.Game
+		rows.Scan(
+			&play.GameKey,
+			&play.PlayId,
+			&play.DriveNum,
+			&play.TeamAbbr,
+			&play.Quarter,
+			&play.Down,
+			&play.YardsToGo,
+			&play.Time,
+			&play.YardLine,
+			&play.FieldPosition,
+			&play.Yards,
+			&play.Description,
+			&play.Note,
+			&game.HomeAbbr,
+			&game.AwayAbbr,
+		)
+		if game.HasTeam(play.TeamAbbr) {
+			play.DefenseAbbr = game.GetOpponent(play.TeamAbbr)
+		}
+		play.Players = []domain.PlayPlayer{}
+		playIndexes[domain.GetPlayKey(play.GameKey, play.PlayId)] = len(plays)
+		plays = append(plays, play)
+	}
+
+	// Add the players involved in each play
+	playerQuery := "select pp.gamekey, pp.playid, pp.playerid, isnull(p.id, 0), pp.name, pp.teamAbbr, " +
+	"pp.statid, pp.yards " +
+	"from PlayPlayer pp " +
+	"join (" + matchingPlaysQuery + ") m " +
+	"on pp.gamekey = m.gamekey " +
+	"and pp.playid = m.playid " +
+	"left join Player p " +
+	"on pp.playerid = p.nflid " +
+	"order by pp.gamekey, pp.playid"
+
+	playerRows, err := db.Query(playerQuery)
+	utils.CheckForError(err)
+	defer playerRows.Close()
+
+	for playerRows.Next() {
+		var gameKey string
+		var playId int
+		var player domain.PlayPlayer
+		playerRows.Scan(
+			&gameKey,
+			&playId,
+			&player.PlayerKey,
+			&player.PlayerId,
+			&player.Name,
+			&player.TeamAbbr,
+			&player.StatId,
+			&player.Yards,
+		)
+
+		if index, ok := playIndexes[domain.GetPlayKey(gameKey, playId)]; ok {
+			plays[index].Players = append(plays[index].Players, player)
+		}
+	}
+
+	return plays
+}
+
+// Get the condition for plays the player was involved in
+func getPlayerInPlayCondition(playerId int) string {
+	return fmt.Sprintf("exists (select 1 from PlayPlayer spp " +
+		"join Player sp on spp.playerid = sp.nflid " +
+		"where spp.gamekey = pl.gamekey and spp.playid = pl.playid and sp.id = %v)", playerId)
+}
+
+// Build a full-text search condition that matches descriptions containing every word in the search text
+func getFullTextSearchCondition(searchText string) string {
+	var terms []string
+	for _, word := range strings.Fields(strings.Replace(searchText, "\"", "", -1)) {
+		terms = append(terms, fmt.Sprintf("\"%v*\"", word))
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// Get the database connection
+func (repo PlaySqlRepository) getDbConn() *sql.DB {
+	return repo.config.getDbConn()
+}
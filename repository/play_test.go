@@ -0,0 +1,22 @@
+package repository
+
+import "testing"
+
+func TestGetFullTextSearchCondition(t *testing.T) {
+	tests := []struct {
+		searchText string
+		expected string
+	}{
+		{"touchdown", `"touchdown*"`},
+		{"two-point  conversion", `"two-point*" AND "conversion*"`},
+		// Quotes would end the terms early, so they're dropped
+		{`"Hail Mary"`, `"Hail*" AND "Mary*"`},
+		{"   ", ""},
+	}
+
+	for _, test := range tests {
+		if condition := getFullTextSearchCondition(test.searchText); condition != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.searchText, condition, test.expected)
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package repository
 
 import (
-	_ "github.com/denisenkom/go-mssqldb"
-	"net/url"
 	"database/sql"
 	"../utils"
 	"../domain"
 	"fmt"
+	"time"
+	"strconv"
+	"strings"
 )
 
 type PlayerSqlRepository struct {
@@ -15,94 +16,263 @@ type PlayerSqlRepository struct {
 
 func NewPlayerSqlRepository() PlayerSqlRepository {
 	repo := PlayerSqlRepository{}
-	repo.config = Configuration {
-		"den1.mssql7.gear.host",
-		0,
-		"nfldata",
-		"database!",
-	}
+	repo.config = newDefaultConfiguration()
 	return repo
 }
 
-// Get players that have the search text in their name
+// Get players that have the search text in their feed name, full name or an alias, with their position
 func (repo PlayerSqlRepository) GetPlayersBySearchText(searchText string) []domain.Player {
 	db := repo.getDbConn()
-	query := fmt.Sprintf("select id, name, teamAbbr from nfldata.dbo.Player where name like '%%%v%%'", searchText)
+	defer db.Close()
+	query := "select p.id, p.name, isnull(p.fullName, ''), p.teamAbbr, isnull(p.positionOverride, ''), " +
+		"isnull(p.rosterPosition, ''), isnull(ps.att, 0), isnull(rs.att, 0), isnull(rc.rec, 0) " +
+		"from nfldata.dbo.Player p " +
+		getStatProfileJoins() +
+		"where " + getNameSearchCondition(searchText)
 	rows, err := db.Query(query)
 	utils.CheckForError(err)
+	defer rows.Close()
 
 	var players []domain.Player
 	for rows.Next() {
 		var currPlayer domain.Player
+		var knownPosition domain.KnownPosition
+		var passingAttempts, rushingAttempts, receptions float64
 		rows.Scan(
 			&currPlayer.Id,
 			&currPlayer.Name,
+			&currPlayer.FullName,
 			&currPlayer.Team,
+			&knownPosition.Override,
+			&knownPosition.Roster,
+			&passingAttempts,
+			&rushingAttempts,
+			&receptions,
 		)
+
+		statProfile := domain.StatLine {
+			"passing": {"att": passingAttempts},
+			"rushing": {"att": rushingAttempts},
+			"receiving": {"rec": receptions},
+		}
+		currPlayer.Position, currPlayer.PositionSource = domain.GetPosition(knownPosition, statProfile)
 		// append the player from the row to the list of players returned
 		players = append(players, currPlayer)
 	}
 	return players
 }
 
+// Get the overridden and roster positions of the players that have one, keyed by player id
+func (repo PlayerSqlRepository) GetKnownPositions() map[int]domain.KnownPosition {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := "select id, isnull(positionOverride, ''), isnull(rosterPosition, '') from nfldata.dbo.Player " +
+		"where positionOverride is not null or rosterPosition is not null"
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	knownPositions := make(map[int]domain.KnownPosition)
+	for rows.Next() {
+		var playerId int
+		var knownPosition domain.KnownPosition
+		rows.Scan(&playerId, &knownPosition.Override, &knownPosition.Roster)
+		knownPositions[playerId] = knownPosition
+	}
+	return knownPositions
+}
+
+// Save the positions, full names and aliases from an imported roster. Players not in the feed yet are saved
+// so they line up once they are
+func (repo PlayerSqlRepository) SaveRoster(entries []domain.RosterEntry) {
+	rosterTvpSaveQuery := ""
+	aliasTvpSaveQuery := ""
+	for _, entry := range entries {
+		newQueryLine := fmt.Sprintf("\nSELECT %v, %v, %v",
+			quoteSqlString(entry.NflId),
+			getNullableSqlString(entry.Position),
+			getNullableSqlString(entry.FullName))
+		rosterTvpSaveQuery = addToTvpQuery(rosterTvpSaveQuery, "PlayerRosterTvp", newQueryLine)
+
+		for _, alias := range entry.Aliases {
+			newQueryLine := fmt.Sprintf("\nSELECT %v, %v", quoteSqlString(entry.NflId), quoteSqlString(alias))
+			aliasTvpSaveQuery = addToTvpQuery(aliasTvpSaveQuery, "PlayerAliasTvp", newQueryLine)
+		}
+	}
+
+	if len(rosterTvpSaveQuery) == 0 {
+		return
+	}
+	rosterTvpSaveQuery += "\nexec SavePlayerRoster @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, rosterTvpSaveQuery)
+	if len(aliasTvpSaveQuery) > 0 {
+		aliasTvpSaveQuery += "\nexec SavePlayerAlias @records = @r"
+		executeModifyQuery(conn, aliasTvpSaveQuery)
+	}
+}
+
+// Set a player's position by hand, over their roster and inferred positions. An empty position clears it
+func (repo PlayerSqlRepository) SavePositionOverride(playerId int, position string) {
+	query := fmt.Sprintf("exec SavePlayerPositionOverride @playerId = %v, @position = %v",
+		playerId,
+		getNullableSqlString(position))
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, query)
+}
+
+// Match the feed name, full name or an alias against each form of the search text, such as "Tom Brady"
+// and "T.Brady". Wildcards in the search text are matched as written
+func getNameSearchCondition(searchText string) string {
+	var conditions []string
+	for _, form := range domain.GetNameSearchForms(searchText) {
+		pattern := quoteSqlString("%" + escapeLikePattern(form) + "%")
+		conditions = append(conditions, fmt.Sprintf("p.name like %v or p.fullName like %v or exists " +
+			"(select 1 from nfldata.dbo.PlayerAlias pa where pa.playerid = p.nflid and pa.alias like %v)",
+			pattern, pattern, pattern))
+	}
+	return "(" + strings.Join(conditions, " or ") + ") "
+}
+
+// Joins giving each player's career passing attempts, rushing attempts and receptions, for inferring their position
+func getStatProfileJoins() string {
+	return "left join (select playerid, sum(att) att from PassingStats group by playerid) ps " +
+		"on p.nflid = ps.playerid " +
+		"left join (select playerid, sum(att) att from RushingStats group by playerid) rs " +
+		"on p.nflid = rs.playerid " +
+		"left join (select playerid, sum(rec) rec from ReceivingStats group by playerid) rc " +
+		"on p.nflid = rc.playerid "
+}
+
 // Get stats for a particular player
-func (repo PlayerSqlRepository) GetPlayerStatsByPlayerId(playerId string) []domain.PlayerStats {
+func (repo PlayerSqlRepository) GetPlayerStatsByPlayerId(playerId int) []domain.PlayerStats {
+	return repo.queryPlayerStats(fmt.Sprintf("where p.id = %v ", playerId))
+}
+
+// Get stats for every player with games between the from and to dates, inclusive
+func (repo PlayerSqlRepository) GetPlayerStatsForDateRange(from time.Time, to time.Time) []domain.PlayerStats {
+	return repo.queryPlayerStats(fmt.Sprintf("where gd.gamedate between '%v' and '%v' ",
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Get stats for every player in a game
+func (repo PlayerSqlRepository) GetPlayerStatsByGameKey(gameKey string) []domain.PlayerStats {
+	return repo.queryPlayerStats(fmt.Sprintf("where pg.gamekey = %v ", quoteSqlString(gameKey)))
+}
+
+// Get stats for the given players with games between the from and to dates, inclusive
+func (repo PlayerSqlRepository) GetPlayerStatsByPlayerIdsForDateRange(playerIds []int, from time.Time,
+	to time.Time) []domain.PlayerStats {
+	if len(playerIds) == 0 {
+		return []domain.PlayerStats{}
+	}
+
+	var ids []string
+	for _, playerId := range playerIds {
+		ids = append(ids, strconv.Itoa(playerId))
+	}
+
+	return repo.queryPlayerStats(fmt.Sprintf("where p.id in (%v) and gd.gamedate between '%v' and '%v' ",
+		strings.Join(ids, ", "),
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Get the game stats for players matching the given where clause, ordered by game date
+func (repo PlayerSqlRepository) queryPlayerStats(whereClause string) []domain.PlayerStats {
 	db := repo.getDbConn()
-	query := fmt.Sprintf(
-		"select p.name, p.teamAbbr, ps.gamedate, " +
-	"ps.att PassAtt, ps.cmp, ps.yds PassYards, ps.tds PassTds, ps.ints," +
-	"ps.twopta PassTwoPta, ps.twoptm PassTwoPta, " +
-	"rus.att RushAtt, rus.yds RushYds, rus.tds RushTds," +
-	"rus.lng RushLng, rus.lngtd RushLngTd," +
-	"rus.twopta RushTwoPta, rus.twoptm RushTwoPtm," +
-	"rs.rec, rs.yds RecYds, rs.tds RecTds," +
-	"rs.lng RecLng, rs.lngtd RecLngTd," +
-	"rs.twopta RecTwoPta, rs.twoptm RecTwoPtm " +
+	defer db.Close()
+	categories := domain.GetStatCategories()
+
+	// Every game a player has stats in any category for
+	var gameDateQueries []string
+	for _, category := range categories {
+		gameDateQueries = append(gameDateQueries, fmt.Sprintf("select playerid, gamedate from %v", category.Table))
+	}
+
+	// Each category's columns, in field order, from its table joined onto the player's games
+	statsColumns := ""
+	statsJoins := ""
+	for _, category := range categories {
+		for _, field := range category.Fields {
+			statsColumns += fmt.Sprintf(", %v.[%v]", category.Table, field.Label)
+		}
+		statsJoins += fmt.Sprintf("left join %v " +
+			"on gd.playerid = %v.playerid " +
+			"and gd.gamedate = %v.gamedate ", category.Table, category.Table, category.Table)
+	}
+
+	query := "select p.id, p.name, isnull(pg.teamAbbr, p.teamAbbr), gd.gamedate, " +
+	"isnull(g.gamekey, ''), isnull(g.homeAbbr, ''), isnull(g.awayAbbr, ''), " +
+	"isnull(g.homeScore, 0), isnull(g.awayScore, 0)" +
+	statsColumns + " " +
 	"from Player p " +
-	"join ReceivingStats rs " +
-	"on p.nflid = rs.playerid " +
-	"join RushingStats rus " +
-	"on p.nflid = rus.playerid " +
-	"and rs.gamedate = rus.gamedate " +
-	"join PassingStats ps " +
-	"on p.nflid = ps.playerid " +
-	"and rs.gamedate = ps.gamedate " +
-	"where p.id = %v " +
-			"order by rs.gamedate", playerId)
-	fmt.Println(query)
+	"join (" + strings.Join(gameDateQueries, " union ") + ") gd " +
+	"on p.nflid = gd.playerid " +
+	statsJoins +
+	"left join PlayerGame pg " +
+	"on p.nflid = pg.playerid " +
+	"and gd.gamedate = pg.gamedate " +
+	"left join Game g " +
+	"on pg.gamekey = g.gamekey " +
+	whereClause +
+	"order by gd.gamedate"
 
 	rows, err := db.Query(query)
 	utils.CheckForError(err)
+	defer rows.Close()
 
 	var playerStats []domain.PlayerStats
 	for rows.Next() {
 		var currPlayerStats domain.PlayerStats
-		rows.Scan(
+		var game domain.Game
+		destinations := []interface{} {
+			&currPlayerStats.PlayerId,
 			&currPlayerStats.Name,
 			&currPlayerStats.TeamAbbr,
 			&currPlayerStats.GameDate,
-			&currPlayerStats.PassingStats.Attempts,
-			&currPlayerStats.PassingStats.Completions,
-			&currPlayerStats.PassingStats.Yards,
-			&currPlayerStats.PassingStats.Touchdowns,
-			&currPlayerStats.PassingStats.Interceptions,
-			&currPlayerStats.PassingStats.TwoPointAttempts,
-			&currPlayerStats.PassingStats.TwoPointSuccesses,
-			&currPlayerStats.RushingStats.Attempts,
-			&currPlayerStats.RushingStats.Yards,
-			&currPlayerStats.RushingStats.Touchdowns,
-			&currPlayerStats.RushingStats.Longest,
-			&currPlayerStats.RushingStats.LongestTouchdown,
-			&currPlayerStats.RushingStats.TwoPointAttempts,
-			&currPlayerStats.RushingStats.TwoPointSuccesses,
-			&currPlayerStats.ReceivingStats.Receptions,
-			&currPlayerStats.ReceivingStats.Yards,
-			&currPlayerStats.ReceivingStats.Touchdowns,
-			&currPlayerStats.ReceivingStats.Longest,
-			&currPlayerStats.ReceivingStats.LongestTouchdown,
-			&currPlayerStats.ReceivingStats.TwoPointAttempts,
-			&currPlayerStats.ReceivingStats.TwoPointSuccesses,
-		)
+			&game.GameKey,
+			&game.HomeAbbr,
+			&game.AwayAbbr,
+			&game.HomeScore,
+			&game.AwayScore,
+		}
+
+		var statsValues []*sql.NullFloat64
+		for _, category := range categories {
+			for range category.Fields {
+				value := &sql.NullFloat64{}
+				statsValues = append(statsValues, value)
+				destinations = append(destinations, value)
+			}
+		}
+		rows.Scan(destinations...)
+
+		// Read the stats values back in the same order as the columns
+		currPlayerStats.StatLine = make(domain.StatLine)
+		valueIndex := 0
+		for _, category := range categories {
+			categoryStats := make(domain.CategoryStats)
+			for _, field := range category.Fields {
+				if statsValues[valueIndex].Valid {
+					categoryStats[field.Label] = statsValues[valueIndex].Float64
+				}
+				valueIndex++
+			}
+			currPlayerStats.StatLine[category.FeedKey] = categoryStats
+		}
+
+		// Games saved before game data was stored have no game context
+		if len(game.GameKey) > 0 {
+			game = domain.NewGame(game.GameKey, currPlayerStats.GameDate, game.HomeAbbr, game.AwayAbbr,
+				game.HomeScore, game.AwayScore)
+			currPlayerStats.SetGame(game)
+		}
+
 		playerStats = append(playerStats, currPlayerStats)
 	}
 
@@ -111,16 +281,5 @@ func (repo PlayerSqlRepository) GetPlayerStatsByPlayerId(playerId string) []doma
 
 // Get the database connection
 func (repo PlayerSqlRepository) getDbConn() *sql.DB {
-	config := repo.config
-	u := &url.URL{
-		Scheme:   "sqlserver",
-		User:     url.UserPassword(config.Username, config.Password),
-		Host:	  config.Host,
-		//Host:     fmt.Sprintf("%s:%d", config.Host, config.Port),
-		// Path:  instance, // if connecting to an instance instead of a port
-	}
-	conn, err := sql.Open("sqlserver", u.String())
-	utils.CheckForError(err)
-
-	return conn
+	return repo.config.getDbConn()
 }
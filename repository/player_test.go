@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		value string
+		expected string
+	}{
+		{"T.Brady", "T.Brady"},
+		{"100%", "100[%]"},
+		{"a_b", "a[_]b"},
+		{"[A-Z]", "[[]A-Z]"},
+		{"[%_]", "[[][%][_]]"},
+	}
+
+	for _, test := range tests {
+		if escaped := escapeLikePattern(test.value); escaped != test.expected {
+			t.Errorf("%v: got %v, expected %v", test.value, escaped, test.expected)
+		}
+	}
+}
+
+func TestGetNameSearchCondition(t *testing.T) {
+	tests := []struct {
+		searchText string
+		expectedPatterns []string
+	}{
+		{"Brady", []string{"'%Brady%'"}},
+		{"T. Brady", []string{"'%T. Brady%'", "'%T.Brady%'"}},
+		{"Tom Brady", []string{"'%Tom Brady%'", "'%T.Brady%'"}},
+		{"O'Brien", []string{"'%O''Brien%'"}},
+		{"_", []string{"'%[_]%'"}},
+		{"%", []string{"'%[%]%'"}},
+	}
+
+	for _, test := range tests {
+		condition := getNameSearchCondition(test.searchText)
+		for _, pattern := range test.expectedPatterns {
+			// Each form is matched against the name, full name and aliases
+			if count := strings.Count(condition, "like " + pattern); count != 3 {
+				t.Errorf("%v: got %v matches of %v in %v, expected 3", test.searchText, count, pattern, condition)
+			}
+		}
+		if count := strings.Count(condition, " like "); count != 3 * len(test.expectedPatterns) {
+			t.Errorf("%v: got %v patterns in %v, expected %v", test.searchText, count / 3, condition,
+				len(test.expectedPatterns))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"../domain"
+	"../utils"
+)
+
+type ScoringSqlRepository struct {
+	config Configuration
+}
+
+// The parts of a ruleset stored as json in the rules column
+type storedScoringRules struct {
+	Points map[string]float64 `json:"points"`
+	Bonuses []domain.ScoringBonus `json:"bonuses"`
+}
+
+func NewScoringSqlRepository() ScoringSqlRepository {
+	repo := ScoringSqlRepository{}
+	repo.config = newDefaultConfiguration()
+	return repo
+}
+
+// Get all the custom rulesets that have been saved
+func (repo ScoringSqlRepository) GetScoringRulesets() []domain.ScoringRuleset {
+	return repo.queryScoringRulesets("select name, rules from nfldata.dbo.ScoringRuleset order by name")
+}
+
+// Get a custom ruleset by name
+func (repo ScoringSqlRepository) GetScoringRuleset(name string) (domain.ScoringRuleset, bool) {
+	query := fmt.Sprintf("select name, rules from nfldata.dbo.ScoringRuleset where name = %v", quoteSqlString(name))
+	rulesets := repo.queryScoringRulesets(query)
+
+	if len(rulesets) == 0 {
+		return domain.ScoringRuleset{}, false
+	}
+	return rulesets[0], true
+}
+
+// Create or replace a custom ruleset
+func (repo ScoringSqlRepository) SaveScoringRuleset(ruleset domain.ScoringRuleset) {
+	rules, err := json.Marshal(storedScoringRules{ruleset.Points, ruleset.Bonuses})
+	utils.CheckForError(err)
+
+	query := fmt.Sprintf("exec SaveScoringRuleset @name = %v, @rules = %v",
+		quoteSqlString(ruleset.Name),
+		quoteSqlString(string(rules)))
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, query)
+}
+
+// Delete a custom ruleset by name
+func (repo ScoringSqlRepository) DeleteScoringRuleset(name string) {
+	query := fmt.Sprintf("delete from nfldata.dbo.ScoringRuleset where name = %v", quoteSqlString(name))
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, query)
+}
+
+// Run a query returning name and rules columns and read them into rulesets
+func (repo ScoringSqlRepository) queryScoringRulesets(query string) []domain.ScoringRuleset {
+	db := repo.getDbConn()
+	defer db.Close()
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	rulesets := []domain.ScoringRuleset{}
+	for rows.Next() {
+		var name string
+		var rules string
+		rows.Scan(&name, &rules)
+
+		var storedRules storedScoringRules
+		err := json.Unmarshal([]byte(rules), &storedRules)
+		if err != nil {
+			continue
+		}
+
+		rulesets = append(rulesets, domain.ScoringRuleset {
+			Name: name,
+			Points: storedRules.Points,
+			Bonuses: storedRules.Bonuses,
+		})
+	}
+	return rulesets
+}
+
+// Get the database connection
+func (repo ScoringSqlRepository) getDbConn() *sql.DB {
+	return repo.config.getDbConn()
+}
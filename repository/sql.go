@@ -0,0 +1,84 @@
+package repository
+
+import (
+	_ "github.com/denisenkom/go-mssqldb"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"../utils"
+)
+
+// Get the configuration for the nfldata database
+func newDefaultConfiguration() Configuration {
+	return Configuration {
+		"den1.mssql7.gear.host",
+		0,
+		"nfldata",
+		"database!",
+	}
+}
+
+// Open a connection to the database in the configuration
+func (config Configuration) getDbConn() *sql.DB {
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(config.Username, config.Password),
+		Host:	  config.Host,
+		//Host:     fmt.Sprintf("%s:%d", config.Host, config.Port),
+		// Path:  instance, // if connecting to an instance instead of a port
+	}
+	conn, err := sql.Open("sqlserver", u.String())
+	utils.CheckForError(err)
+
+	return conn
+}
+
+// Quote a string value so it can be put directly into a query string
+func quoteSqlString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// Escape the wildcards in a string so a like pattern matches it as written. The bracket is escaped first,
+// as it's used to escape the others
+func escapeLikePattern(value string) string {
+	value = strings.Replace(value, "[", "[[]", -1)
+	value = strings.Replace(value, "%", "[%]", -1)
+	return strings.Replace(value, "_", "[_]", -1)
+}
+
+// Quote a string for a query, or null when it's empty
+func getNullableSqlString(value string) string {
+	if len(value) == 0 {
+		return "null"
+	}
+	return quoteSqlString(value)
+}
+
+// Format a number for a query, or null when it's unknown
+func getNullableSqlInt(value *int) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", *value)
+}
+
+// Format a bool as a bit value for a query string
+func formatBitForQuery(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Add a line of data to a tvp insert query, declaring the tvp for the first line
+func addToTvpQuery(tvpCurrQuery string, tvpType string, newQueryLine string) string {
+	if len(tvpCurrQuery) == 0 {
+		tvpCurrQuery += fmt.Sprintf("\nDECLARE @r %v\n", tvpType)
+		tvpCurrQuery += fmt.Sprintf("INSERT INTO @r %v", newQueryLine)
+		return tvpCurrQuery
+	}
+
+	tvpCurrQuery += fmt.Sprintf(" UNION %v", newQueryLine)
+	return tvpCurrQuery
+}
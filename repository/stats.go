@@ -3,9 +3,6 @@ package repository
 import (
 	"../domain"
 
-	_ "github.com/denisenkom/go-mssqldb"
-	"net/url"
-	"../utils"
 	"database/sql"
 	"context"
 	"fmt"
@@ -25,12 +22,7 @@ type tvpSaveData struct {
 
 func NewStatsSqlRepository() StatsSqlRepository {
 	repo := StatsSqlRepository {}
-	repo.config = Configuration {
-		"den1.mssql7.gear.host",
-		0,
-		"nfldata",
-		"database!",
-	}
+	repo.config = newDefaultConfiguration()
 	return repo
 }
 
@@ -45,34 +37,41 @@ func newTvpSaveData(playerKey string, playerData domain.PlayerStats, statsType s
 // Save the player stats in the given map of player key/id to player data
 func (repo StatsSqlRepository) SavePlayerStatsBatch(statsMap map[string]domain.PlayerStats) {
 	playerTvpSaveQuery := ""
-	passingTvpSaveQuery := ""
-	rushingTvpSaveQuery := ""
-	receivingTvpSaveQuery := ""
+	playerGameTvpSaveQuery := ""
+	statsTvpSaveQueries := make(map[string]string)
+	categories := domain.GetStatCategories()
+
 	// Iterate through each player in the player data and add to the save query
 	for playerKey, playerData := range statsMap {
-		passingTvpSaveData := newTvpSaveData(playerKey, playerData, "passing")
-		passingTvpSaveQuery = addToStatsTvp(passingTvpSaveQuery, passingTvpSaveData)
-
-		rushingTvpSaveData := newTvpSaveData(playerKey, playerData, "rushing")
-		rushingTvpSaveQuery = addToStatsTvp(rushingTvpSaveQuery, rushingTvpSaveData)
+		for _, category := range categories {
+			// Only save the categories the player has stats for
+			if len(playerData.StatLine[category.FeedKey]) == 0 {
+				continue
+			}
 
-		receivingTvpSaveData := newTvpSaveData(playerKey, playerData, "receiving")
-		receivingTvpSaveQuery = addToStatsTvp(receivingTvpSaveQuery, receivingTvpSaveData)
+			tvpSaveData := newTvpSaveData(playerKey, playerData, category.FeedKey)
+			statsTvpSaveQueries[category.FeedKey] = addToStatsTvp(statsTvpSaveQueries[category.FeedKey], tvpSaveData)
+		}
 
 		playerTvpSaveQuery = addToPlayerTvp(playerTvpSaveQuery, playerKey, playerData)
+		playerGameTvpSaveQuery = addToPlayerGameTvp(playerGameTvpSaveQuery, playerKey, playerData)
 	}
 
 	playerTvpSaveQuery += "\nexec SavePlayer @records = @r"
-	passingTvpSaveQuery += "\nexec SavePassingStats @records = @r"
-	rushingTvpSaveQuery += "\nexec SaveRushingStats @records = @r"
-	receivingTvpSaveQuery += "\nexec SaveReceivingStats @records = @r"
+	playerGameTvpSaveQuery += "\nexec SavePlayerGame @records = @r"
 
 	conn := repo.getDbConn()
 	defer conn.Close()
-	executeModifyQuery(*conn, playerTvpSaveQuery)
-	executeModifyQuery(*conn, passingTvpSaveQuery)
-	executeModifyQuery(*conn, rushingTvpSaveQuery)
-	executeModifyQuery(*conn, receivingTvpSaveQuery)
+	executeModifyQuery(conn, playerTvpSaveQuery)
+	for _, category := range categories {
+		statsTvpSaveQuery, ok := statsTvpSaveQueries[category.FeedKey]
+		if !ok {
+			continue
+		}
+		statsTvpSaveQuery += fmt.Sprintf("\nexec Save%v @records = @r", category.Table)
+		executeModifyQuery(conn, statsTvpSaveQuery)
+	}
+	executeModifyQuery(conn, playerGameTvpSaveQuery)
 
 }
 
@@ -90,52 +89,63 @@ func addToPlayerTvp(tvpCurrQuery string, playerKey string, playerData domain.Pla
 	return tvpCurrQuery
 }
 
+// Save the teams and final score of a game
+func (repo StatsSqlRepository) SaveGame(game domain.Game) {
+	query := "DECLARE @r GameTvp\n"
+	query += fmt.Sprintf("INSERT INTO @r\nSELECT '%v', '%v', %v, %v, '%v', '%v', %v, %v",
+		game.GameKey,
+		formatDateForQuery(game.GameDate),
+		game.Season,
+		game.Week,
+		game.HomeAbbr,
+		game.AwayAbbr,
+		game.HomeScore,
+		game.AwayScore)
+	query += "\nexec SaveGame @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, query)
+}
+
+// Add the next line of data to the player game tvp, linking the player's stats to the game and their team in it
+func addToPlayerGameTvp(tvpCurrQuery string, playerKey string, playerData domain.PlayerStats) string {
+	newQueryLine := fmt.Sprintf("\nSELECT '%v', '%v', '%v', '%v'",
+		playerKey,
+		playerData.GameKey,
+		formatDateForQuery(playerData.GameDate),
+		playerData.TeamAbbr)
+
+	if len(tvpCurrQuery) == 0 {
+		tvpCurrQuery += fmt.Sprintf("\nDECLARE @r PlayerGameTvp\n")
+		tvpCurrQuery += fmt.Sprintf("INSERT INTO @r %v", newQueryLine)
+		return tvpCurrQuery
+	}
+
+	tvpCurrQuery += fmt.Sprintf(" UNION %v", newQueryLine)
+	return tvpCurrQuery
+}
+
 // Add the next line of data to the given tvp Query for saving stats
 func addToStatsTvp(tvpCurrQuery string, data tvpSaveData) string {
 	statsType := strings.ToLower(data.statsType)
-	newQueryLine := ""
+	category, ok := domain.GetStatCategory(statsType)
+	if !ok {
+		return tvpCurrQuery
+	}
+
 	gameDate := formatDateForQuery(data.playerData.GameDate)
 
-	// format the new query string line for the given data
-	switch statsType {
-	case "passing":
-		newQueryLine = fmt.Sprintf(
-			"select '%v', '%v', %v, %v, %v, %v, %v, %v, %v",
-			data.playerKey,
-			gameDate,
-			data.playerData.PassingStats.Attempts,
-			data.playerData.PassingStats.Completions,
-			data.playerData.PassingStats.Yards,
-			data.playerData.PassingStats.Touchdowns,
-			data.playerData.PassingStats.Interceptions,
-			data.playerData.PassingStats.TwoPointAttempts,
-			data.playerData.PassingStats.TwoPointSuccesses)
-		break
-	case "rushing":
-		newQueryLine = fmt.Sprintf(
-			"select '%v', '%v', %v, %v, %v, %v, %v, %v, %v",
-			data.playerKey,
-			gameDate,
-			data.playerData.RushingStats.Attempts,
-			data.playerData.RushingStats.Yards,
-			data.playerData.RushingStats.Touchdowns,
-			data.playerData.RushingStats.Longest,
-			data.playerData.RushingStats.LongestTouchdown,
-			data.playerData.RushingStats.TwoPointAttempts,
-			data.playerData.RushingStats.TwoPointSuccesses)
-		break
-	case "receiving":
-		newQueryLine = fmt.Sprintf(
-			"select '%v', '%v', %v, %v, %v, %v, %v, %v, %v",
-			data.playerKey,
-			gameDate,
-			data.playerData.ReceivingStats.Receptions,
-			data.playerData.ReceivingStats.Yards,
-			data.playerData.ReceivingStats.Touchdowns,
-			data.playerData.ReceivingStats.Longest,
-			data.playerData.ReceivingStats.LongestTouchdown,
-			data.playerData.ReceivingStats.TwoPointAttempts,
-			data.playerData.ReceivingStats.TwoPointSuccesses)
+	// format the new query string line for the given data, with the values in the category's field order.
+	// Unknown stats are saved as NULL
+	newQueryLine := fmt.Sprintf("select '%v', '%v'", data.playerKey, gameDate)
+	for _, field := range category.Fields {
+		value, ok := data.playerData.StatLine[category.FeedKey][field.Label]
+		if !ok {
+			newQueryLine += ", NULL"
+			continue
+		}
+		newQueryLine += fmt.Sprintf(", %v", value)
 	}
 
 	// If the current save query has no data, add initial tvp declaration
@@ -160,22 +170,11 @@ func formatDateForQuery(dateTime time.Time) string {
 
 
 func (repo StatsSqlRepository) getDbConn() *sql.DB {
-	config := repo.config
-	u := &url.URL{
-		Scheme:   "sqlserver",
-		User:     url.UserPassword(config.Username, config.Password),
-		Host:	  config.Host,
-		//Host:     fmt.Sprintf("%s:%d", config.Host, config.Port),
-		// Path:  instance, // if connecting to an instance instead of a port
-	}
-	conn, err := sql.Open("sqlserver", u.String())
-	utils.CheckForError(err)
-
-	return conn
+	return repo.config.getDbConn()
 }
 
 // execute a sql query that inserts or updates data and doesn't return any rows
-func executeModifyQuery(conn sql.DB, query string) {
+func executeModifyQuery(conn *sql.DB, query string) {
 	ctx := context.Background()
 	if len(query) == 0 {
 		return
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"../domain"
+	"../utils"
+)
+
+type TeamSqlRepository struct {
+	config Configuration
+}
+
+func NewTeamSqlRepository() TeamSqlRepository {
+	repo := TeamSqlRepository{}
+	repo.config = newDefaultConfiguration()
+	return repo
+}
+
+// Get every team's game stats for games between the from and to dates, inclusive
+func (repo TeamSqlRepository) GetTeamGameStatsForDateRange(from time.Time, to time.Time) []domain.TeamGameStats {
+	return repo.queryTeamGameStats(fmt.Sprintf("where ts.gamedate between '%v' and '%v' ",
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Get a team's game stats for games between the from and to dates, inclusive
+func (repo TeamSqlRepository) GetTeamGameStatsByTeam(teamAbbr string, from time.Time,
+	to time.Time) []domain.TeamGameStats {
+	return repo.queryTeamGameStats(fmt.Sprintf("where ts.teamAbbr = %v and ts.gamedate between '%v' and '%v' ",
+		quoteSqlString(teamAbbr),
+		formatDateForQuery(from),
+		formatDateForQuery(to)))
+}
+
+// Save a team's stats for a game. Unknown stats are saved as null
+func (repo TeamSqlRepository) SaveTeamGameStats(teamGameStats []domain.TeamGameStats) {
+	tvpSaveQuery := ""
+	for _, teamGame := range teamGameStats {
+		newQueryLine := fmt.Sprintf("\nSELECT '%v', '%v', '%v', %v, %v, %v, %v, %v, %v, %v, %v",
+			teamGame.TeamAbbr,
+			teamGame.GameKey,
+			formatDateForQuery(teamGame.GameDate),
+			getNullableSqlInt(teamGame.Stats.FirstDowns),
+			getNullableSqlInt(teamGame.Stats.TotalYards),
+			getNullableSqlInt(teamGame.Stats.PassingYards),
+			getNullableSqlInt(teamGame.Stats.RushingYards),
+			getNullableSqlInt(teamGame.Stats.Penalties),
+			getNullableSqlInt(teamGame.Stats.PenaltyYards),
+			getNullableSqlInt(teamGame.Stats.Turnovers),
+			getNullableSqlInt(teamGame.Stats.TimeOfPossession))
+
+		if len(tvpSaveQuery) == 0 {
+			tvpSaveQuery += "\nDECLARE @r TeamGameStatsTvp\n"
+			tvpSaveQuery += fmt.Sprintf("INSERT INTO @r %v", newQueryLine)
+			continue
+		}
+		tvpSaveQuery += fmt.Sprintf(" UNION %v", newQueryLine)
+	}
+
+	if len(tvpSaveQuery) == 0 {
+		return
+	}
+	tvpSaveQuery += "\nexec SaveTeamGameStats @records = @r"
+
+	conn := repo.getDbConn()
+	defer conn.Close()
+	executeModifyQuery(conn, tvpSaveQuery)
+}
+
+// Get the team game stats matching the given where clause, ordered by game date
+func (repo TeamSqlRepository) queryTeamGameStats(whereClause string) []domain.TeamGameStats {
+	db := repo.getDbConn()
+	defer db.Close()
+	query := "select ts.teamAbbr, ts.gamedate, g.gamekey, g.homeAbbr, g.awayAbbr, " +
+	"g.homeScore, g.awayScore, " +
+	"ts.totfd, ts.totyds, ts.pyds, ts.ryds, ts.pen, ts.penyds, ts.trnovr, ts.[top] " +
+	"from TeamGameStats ts " +
+	"join Game g " +
+	"on ts.gamekey = g.gamekey " +
+	whereClause +
+	"order by ts.gamedate, ts.teamAbbr"
+
+	rows, err := db.Query(query)
+	utils.CheckForError(err)
+	defer rows.Close()
+
+	teamGameStats := []domain.TeamGameStats{}
+	for rows.Next() {
+		var teamGame domain.TeamGameStats
+		var game domain.Game
+		rows.Scan(
+			&teamGame.TeamAbbr,
+			&teamGame.GameDate,
+			&game.GameKey,
+			&game.HomeAbbr,
+			&game.AwayAbbr,
+			&game.HomeScore,
+			&game.AwayScore,
+			&teamGame.Stats.FirstDowns,
+			&teamGame.Stats.TotalYards,
+			&teamGame.Stats.PassingYards,
+			&teamGame.Stats.RushingYards,
+			&teamGame.Stats.Penalties,
+			&teamGame.Stats.PenaltyYards,
+			&teamGame.Stats.Turnovers,
+			&teamGame.Stats.TimeOfPossession,
+		)
+
+		game = domain.NewGame(game.GameKey, teamGame.GameDate, game.HomeAbbr, game.AwayAbbr,
+			game.HomeScore, game.AwayScore)
+		teamGame.GameContext = domain.NewGameContext(game, teamGame.TeamAbbr)
+		teamGameStats = append(teamGameStats, teamGame)
+	}
+
+	return teamGameStats
+}
+
+// Get the database connection
+func (repo TeamSqlRepository) getDbConn() *sql.DB {
+	return repo.config.getDbConn()
+}
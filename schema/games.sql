@@ -0,0 +1,178 @@
+-- Games, the team each player played for in them, linescores, scoring plays and the league schedule
+
+CREATE TABLE Game (
+	gamekey varchar(10) NOT NULL PRIMARY KEY,
+	gamedate date NOT NULL,
+	season int NOT NULL,
+	week int NOT NULL,
+	homeAbbr varchar(3) NOT NULL,
+	awayAbbr varchar(3) NOT NULL,
+	homeScore int NOT NULL,
+	awayScore int NOT NULL
+)
+CREATE INDEX IX_Game_Season ON Game (season)
+CREATE INDEX IX_Game_Gamedate ON Game (gamedate)
+GO
+
+CREATE TYPE GameTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	season int NOT NULL,
+	week int NOT NULL,
+	homeAbbr varchar(3) NOT NULL,
+	awayAbbr varchar(3) NOT NULL,
+	homeScore int NOT NULL,
+	awayScore int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveGame @records GameTvp READONLY AS
+BEGIN
+	MERGE Game t
+	USING @records s
+	ON t.gamekey = s.gamekey
+	WHEN MATCHED THEN UPDATE SET gamedate = s.gamedate, season = s.season, week = s.week,
+		homeAbbr = s.homeAbbr, awayAbbr = s.awayAbbr, homeScore = s.homeScore, awayScore = s.awayScore
+	WHEN NOT MATCHED THEN INSERT (gamekey, gamedate, season, week, homeAbbr, awayAbbr, homeScore, awayScore)
+		VALUES (s.gamekey, s.gamedate, s.season, s.week, s.homeAbbr, s.awayAbbr, s.homeScore, s.awayScore);
+END
+GO
+
+-- The game and team for each player's stats. playerid is the feed's id, Player.nflid
+CREATE TABLE PlayerGame (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE PlayerGameTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	teamAbbr varchar(3) NOT NULL
+)
+GO
+
+CREATE PROCEDURE SavePlayerGame @records PlayerGameTvp READONLY AS
+BEGIN
+	MERGE PlayerGame t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET gamekey = s.gamekey, teamAbbr = s.teamAbbr
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, gamekey, teamAbbr)
+		VALUES (s.playerid, s.gamedate, s.gamekey, s.teamAbbr);
+END
+GO
+
+-- Each team's points per quarter, with overtime as quarter 5
+CREATE TABLE GameLinescore (
+	gamekey varchar(10) NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	points int NOT NULL,
+	PRIMARY KEY (gamekey, teamAbbr, qtr)
+)
+GO
+
+CREATE TYPE GameLinescoreTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	points int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveGameLinescore @records GameLinescoreTvp READONLY AS
+BEGIN
+	MERGE GameLinescore t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.teamAbbr = s.teamAbbr AND t.qtr = s.qtr
+	WHEN MATCHED THEN UPDATE SET points = s.points
+	WHEN NOT MATCHED THEN INSERT (gamekey, teamAbbr, qtr, points)
+		VALUES (s.gamekey, s.teamAbbr, s.qtr, s.points);
+END
+GO
+
+-- The scoring summary. scorerid is the feed's id for the scorer
+CREATE TABLE ScoringPlay (
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	type varchar(3) NOT NULL,
+	description nvarchar(500) NOT NULL,
+	scorerid varchar(20) NOT NULL,
+	scorer nvarchar(50) NOT NULL,
+	points int NOT NULL,
+	PRIMARY KEY (gamekey, playid)
+)
+GO
+
+CREATE TYPE ScoringPlayTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	type varchar(3) NOT NULL,
+	description nvarchar(500) NOT NULL,
+	scorerid varchar(20) NOT NULL,
+	scorer nvarchar(50) NOT NULL,
+	points int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveScoringPlay @records ScoringPlayTvp READONLY AS
+BEGIN
+	MERGE ScoringPlay t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.playid = s.playid
+	WHEN MATCHED THEN UPDATE SET teamAbbr = s.teamAbbr, qtr = s.qtr, type = s.type, description = s.description,
+		scorerid = s.scorerid, scorer = s.scorer, points = s.points
+	WHEN NOT MATCHED THEN INSERT (gamekey, playid, teamAbbr, qtr, type, description, scorerid, scorer, points)
+		VALUES (s.gamekey, s.playid, s.teamAbbr, s.qtr, s.type, s.description, s.scorerid, s.scorer, s.points);
+END
+GO
+
+-- The regular season schedule from the score strip, including games that haven't been played.
+-- status is P before the game, the quarter while it's on and F or FO once it's over
+CREATE TABLE ScheduledGame (
+	gamekey varchar(10) NOT NULL PRIMARY KEY,
+	gamedate date NOT NULL,
+	season int NOT NULL,
+	week int NOT NULL,
+	homeAbbr varchar(3) NOT NULL,
+	awayAbbr varchar(3) NOT NULL,
+	homeScore int NOT NULL,
+	awayScore int NOT NULL,
+	status varchar(3) NOT NULL
+)
+CREATE INDEX IX_ScheduledGame_Season ON ScheduledGame (season)
+GO
+
+CREATE TYPE ScheduledGameTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	season int NOT NULL,
+	week int NOT NULL,
+	homeAbbr varchar(3) NOT NULL,
+	awayAbbr varchar(3) NOT NULL,
+	homeScore int NOT NULL,
+	awayScore int NOT NULL,
+	status varchar(3) NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveScheduledGame @records ScheduledGameTvp READONLY AS
+BEGIN
+	MERGE ScheduledGame t
+	USING @records s
+	ON t.gamekey = s.gamekey
+	WHEN MATCHED THEN UPDATE SET gamedate = s.gamedate, season = s.season, week = s.week, homeAbbr = s.homeAbbr,
+		awayAbbr = s.awayAbbr, homeScore = s.homeScore, awayScore = s.awayScore, status = s.status
+	WHEN NOT MATCHED THEN INSERT (gamekey, gamedate, season, week, homeAbbr, awayAbbr, homeScore, awayScore, status)
+		VALUES (s.gamekey, s.gamedate, s.season, s.week, s.homeAbbr, s.awayAbbr, s.homeScore, s.awayScore, s.status);
+END
+GO
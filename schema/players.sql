@@ -0,0 +1,85 @@
+-- Player positions, full names and aliases from imported rosters and overrides set by hand
+
+ALTER TABLE Player ADD
+	rosterPosition varchar(4) NULL,
+	positionOverride varchar(4) NULL
+GO
+
+CREATE TYPE PlayerRosterTvp AS TABLE (
+	nflid varchar(20) NOT NULL,
+	position varchar(4) NULL
+)
+GO
+
+-- Players not in the feed yet are added without a name until the feed saves them
+CREATE PROCEDURE SavePlayerRoster @records PlayerRosterTvp READONLY AS
+BEGIN
+	MERGE Player t
+	USING @records s
+	ON t.nflid = s.nflid
+	WHEN MATCHED THEN UPDATE SET rosterPosition = s.position
+	WHEN NOT MATCHED THEN INSERT (nflid, name, teamAbbr, rosterPosition)
+		VALUES (s.nflid, '', '', s.position);
+END
+GO
+
+-- A null position clears the override
+CREATE PROCEDURE SavePlayerPositionOverride @playerId int, @position varchar(4) AS
+BEGIN
+	UPDATE Player SET positionOverride = @position WHERE id = @playerId
+END
+GO
+
+ALTER TABLE Player ADD fullName nvarchar(100) NULL
+GO
+
+-- Other names a player is searched by. playerid is the feed's id, Player.nflid
+CREATE TABLE PlayerAlias (
+	playerid varchar(20) NOT NULL,
+	alias nvarchar(100) NOT NULL,
+	PRIMARY KEY (playerid, alias)
+)
+GO
+
+-- The roster type gets the full name, so the procedure using it is dropped first
+DROP PROCEDURE SavePlayerRoster
+GO
+
+DROP TYPE PlayerRosterTvp
+GO
+
+-- position and fullName are null when the roster leaves them out, which keeps what's already saved
+CREATE TYPE PlayerRosterTvp AS TABLE (
+	nflid varchar(20) NOT NULL,
+	position varchar(4) NULL,
+	fullName nvarchar(100) NULL
+)
+GO
+
+-- Players not in the feed yet are added under their full name until the feed saves them
+CREATE PROCEDURE SavePlayerRoster @records PlayerRosterTvp READONLY AS
+BEGIN
+	MERGE Player t
+	USING @records s
+	ON t.nflid = s.nflid
+	WHEN MATCHED THEN UPDATE SET rosterPosition = isnull(s.position, t.rosterPosition),
+		fullName = isnull(s.fullName, t.fullName)
+	WHEN NOT MATCHED THEN INSERT (nflid, name, teamAbbr, rosterPosition, fullName)
+		VALUES (s.nflid, isnull(s.fullName, ''), '', s.position, s.fullName);
+END
+GO
+
+CREATE TYPE PlayerAliasTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	alias nvarchar(100) NOT NULL
+)
+GO
+
+CREATE PROCEDURE SavePlayerAlias @records PlayerAliasTvp READONLY AS
+BEGIN
+	INSERT INTO PlayerAlias (playerid, alias)
+	SELECT DISTINCT s.playerid, s.alias
+	FROM @records s
+	WHERE NOT EXISTS (SELECT 1 FROM PlayerAlias pa WHERE pa.playerid = s.playerid AND pa.alias = s.alias)
+END
+GO
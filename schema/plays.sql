@@ -0,0 +1,95 @@
+-- Play-by-play and the players in each play, with the full-text index play search uses
+
+-- teamAbbr is empty for plays without a team with the ball, such as timeouts. id is only there because a
+-- full-text index needs a single column unique key
+CREATE TABLE Play (
+	id int IDENTITY NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	drivenum int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	down int NOT NULL,
+	ydstogo int NOT NULL,
+	time varchar(5) NOT NULL,
+	yrdln varchar(10) NOT NULL,
+	fieldpos int NOT NULL,
+	ydsnet int NOT NULL,
+	description nvarchar(1000) NOT NULL,
+	note varchar(20) NOT NULL,
+	PRIMARY KEY (gamekey, playid)
+)
+CREATE UNIQUE INDEX UX_Play_Id ON Play (id)
+GO
+
+CREATE FULLTEXT CATALOG PlayCatalog
+CREATE FULLTEXT INDEX ON Play (description) KEY INDEX UX_Play_Id ON PlayCatalog WITH CHANGE_TRACKING AUTO
+GO
+
+CREATE TYPE PlayTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	drivenum int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	qtr int NOT NULL,
+	down int NOT NULL,
+	ydstogo int NOT NULL,
+	time varchar(5) NOT NULL,
+	yrdln varchar(10) NOT NULL,
+	fieldpos int NOT NULL,
+	ydsnet int NOT NULL,
+	description nvarchar(1000) NOT NULL,
+	note varchar(20) NOT NULL
+)
+GO
+
+CREATE PROCEDURE SavePlay @records PlayTvp READONLY AS
+BEGIN
+	MERGE Play t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.playid = s.playid
+	WHEN MATCHED THEN UPDATE SET drivenum = s.drivenum, teamAbbr = s.teamAbbr, qtr = s.qtr, down = s.down,
+		ydstogo = s.ydstogo, time = s.time, yrdln = s.yrdln, fieldpos = s.fieldpos, ydsnet = s.ydsnet,
+		description = s.description, note = s.note
+	WHEN NOT MATCHED THEN INSERT (gamekey, playid, drivenum, teamAbbr, qtr, down, ydstogo, time, yrdln, fieldpos,
+		ydsnet, description, note)
+		VALUES (s.gamekey, s.playid, s.drivenum, s.teamAbbr, s.qtr, s.down, s.ydstogo, s.time, s.yrdln, s.fieldpos,
+		s.ydsnet, s.description, s.note);
+END
+GO
+
+-- A player has a row for every stat they recorded in a play. playerid is the feed's id, Player.nflid
+CREATE TABLE PlayPlayer (
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	playerid varchar(20) NOT NULL,
+	name nvarchar(50) NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	statid int NOT NULL,
+	yards int NOT NULL,
+	PRIMARY KEY (gamekey, playid, playerid, statid)
+)
+CREATE INDEX IX_PlayPlayer_Playerid ON PlayPlayer (playerid)
+GO
+
+CREATE TYPE PlayPlayerTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	playid int NOT NULL,
+	playerid varchar(20) NOT NULL,
+	name nvarchar(50) NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	statid int NOT NULL,
+	yards int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SavePlayPlayer @records PlayPlayerTvp READONLY AS
+BEGIN
+	MERGE PlayPlayer t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.playid = s.playid AND t.playerid = s.playerid AND t.statid = s.statid
+	WHEN MATCHED THEN UPDATE SET name = s.name, teamAbbr = s.teamAbbr, yards = s.yards
+	WHEN NOT MATCHED THEN INSERT (gamekey, playid, playerid, name, teamAbbr, statid, yards)
+		VALUES (s.gamekey, s.playid, s.playerid, s.name, s.teamAbbr, s.statid, s.yards);
+END
+GO
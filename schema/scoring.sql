@@ -0,0 +1,17 @@
+-- Custom fantasy scoring rulesets. rules is the json of the ruleset's points and bonuses
+
+CREATE TABLE ScoringRuleset (
+	name nvarchar(50) NOT NULL PRIMARY KEY,
+	rules nvarchar(max) NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveScoringRuleset @name nvarchar(50), @rules nvarchar(max) AS
+BEGIN
+	MERGE ScoringRuleset t
+	USING (SELECT @name AS name, @rules AS rules) s
+	ON t.name = s.name
+	WHEN MATCHED THEN UPDATE SET rules = s.rules
+	WHEN NOT MATCHED THEN INSERT (name, rules) VALUES (s.name, s.rules);
+END
+GO
@@ -0,0 +1,333 @@
+-- Tables, table types and procedures for the stat categories added after passing, rushing and receiving.
+-- Each has a row per player per game, keyed by the feed's player id, Player.nflid
+
+-- Kicking stats
+CREATE TABLE KickingStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[fgm] int NULL,
+	[fga] int NULL,
+	[fgyds] int NULL,
+	[xpmade] int NULL,
+	[xpa] int NULL,
+	[totpfg] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE kickingStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[fgm] int NULL,
+	[fga] int NULL,
+	[fgyds] int NULL,
+	[xpmade] int NULL,
+	[xpa] int NULL,
+	[totpfg] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveKickingStats @records kickingStatsTvp READONLY AS
+BEGIN
+	MERGE KickingStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [fgm] = s.[fgm], [fga] = s.[fga], [fgyds] = s.[fgyds], [xpmade] = s.[xpmade], [xpa] = s.[xpa], [totpfg] = s.[totpfg]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [fgm], [fga], [fgyds], [xpmade], [xpa], [totpfg])
+		VALUES (s.playerid, s.gamedate, s.[fgm], s.[fga], s.[fgyds], s.[xpmade], s.[xpa], s.[totpfg]);
+END
+GO
+
+-- Defensive stats. Sacks can be shared, so they can be halves
+CREATE TABLE DefenseStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[tkl] int NULL,
+	[ast] int NULL,
+	[sk] decimal(4,1) NULL,
+	[int] int NULL,
+	[ffum] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE defenseStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[tkl] int NULL,
+	[ast] int NULL,
+	[sk] decimal(4,1) NULL,
+	[int] int NULL,
+	[ffum] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveDefenseStats @records defenseStatsTvp READONLY AS
+BEGIN
+	MERGE DefenseStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [tkl] = s.[tkl], [ast] = s.[ast], [sk] = s.[sk], [int] = s.[int], [ffum] = s.[ffum]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [tkl], [ast], [sk], [int], [ffum])
+		VALUES (s.playerid, s.gamedate, s.[tkl], s.[ast], s.[sk], s.[int], s.[ffum]);
+END
+GO
+
+-- Fumbles
+CREATE TABLE FumbleStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[tot] int NULL,
+	[rcv] int NULL,
+	[trcv] int NULL,
+	[yds] int NULL,
+	[lost] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE fumblesStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[tot] int NULL,
+	[rcv] int NULL,
+	[trcv] int NULL,
+	[yds] int NULL,
+	[lost] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveFumbleStats @records fumblesStatsTvp READONLY AS
+BEGIN
+	MERGE FumbleStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [tot] = s.[tot], [rcv] = s.[rcv], [trcv] = s.[trcv], [yds] = s.[yds], [lost] = s.[lost]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [tot], [rcv], [trcv], [yds], [lost])
+		VALUES (s.playerid, s.gamedate, s.[tot], s.[rcv], s.[trcv], s.[yds], s.[lost]);
+END
+GO
+
+-- Kick returns. yds is estimated from the number of returns and the feed's rounded average
+CREATE TABLE KickReturnStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[ret] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE kickretStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[ret] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveKickReturnStats @records kickretStatsTvp READONLY AS
+BEGIN
+	MERGE KickReturnStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [ret] = s.[ret], [yds] = s.[yds], [tds] = s.[tds], [lng] = s.[lng], [lngtd] = s.[lngtd]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [ret], [yds], [tds], [lng], [lngtd])
+		VALUES (s.playerid, s.gamedate, s.[ret], s.[yds], s.[tds], s.[lng], s.[lngtd]);
+END
+GO
+
+-- Punt returns, estimated the same way as kick returns
+CREATE TABLE PuntReturnStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[ret] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE puntretStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[ret] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL
+)
+GO
+
+CREATE PROCEDURE SavePuntReturnStats @records puntretStatsTvp READONLY AS
+BEGIN
+	MERGE PuntReturnStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [ret] = s.[ret], [yds] = s.[yds], [tds] = s.[tds], [lng] = s.[lng], [lngtd] = s.[lngtd]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [ret], [yds], [tds], [lng], [lngtd])
+		VALUES (s.playerid, s.gamedate, s.[ret], s.[yds], s.[tds], s.[lng], s.[lngtd]);
+END
+GO
+
+-- Punting
+CREATE TABLE PuntingStats (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[pts] int NULL,
+	[yds] int NULL,
+	[avg] decimal(5,1) NULL,
+	[i20] int NULL,
+	[lng] int NULL,
+	PRIMARY KEY (playerid, gamedate)
+)
+GO
+
+CREATE TYPE puntingStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[pts] int NULL,
+	[yds] int NULL,
+	[avg] decimal(5,1) NULL,
+	[i20] int NULL,
+	[lng] int NULL
+)
+GO
+
+CREATE PROCEDURE SavePuntingStats @records puntingStatsTvp READONLY AS
+BEGIN
+	MERGE PuntingStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [pts] = s.[pts], [yds] = s.[yds], [avg] = s.[avg], [i20] = s.[i20], [lng] = s.[lng]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [pts], [yds], [avg], [i20], [lng])
+		VALUES (s.playerid, s.gamedate, s.[pts], s.[yds], s.[avg], s.[i20], s.[lng]);
+END
+GO
+
+-- Passing, rushing and receiving stats that weren't in the feed are saved as NULL rather than 0,
+-- so their columns and table types have to allow it. The procedures are dropped first as they use the types
+ALTER TABLE PassingStats ALTER COLUMN [att] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [cmp] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [yds] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [tds] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [ints] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [twopta] int NULL
+ALTER TABLE PassingStats ALTER COLUMN [twoptm] int NULL
+GO
+
+ALTER TABLE RushingStats ALTER COLUMN [att] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [yds] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [tds] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [lng] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [lngtd] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [twopta] int NULL
+ALTER TABLE RushingStats ALTER COLUMN [twoptm] int NULL
+GO
+
+ALTER TABLE ReceivingStats ALTER COLUMN [rec] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [yds] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [tds] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [lng] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [lngtd] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [twopta] int NULL
+ALTER TABLE ReceivingStats ALTER COLUMN [twoptm] int NULL
+GO
+
+DROP PROCEDURE SavePassingStats
+GO
+
+DROP TYPE passingStatsTvp
+GO
+
+CREATE TYPE passingStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[att] int NULL,
+	[cmp] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[ints] int NULL,
+	[twopta] int NULL,
+	[twoptm] int NULL
+)
+GO
+
+CREATE PROCEDURE SavePassingStats @records passingStatsTvp READONLY AS
+BEGIN
+	MERGE PassingStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [att] = s.[att], [cmp] = s.[cmp], [yds] = s.[yds], [tds] = s.[tds], [ints] = s.[ints], [twopta] = s.[twopta], [twoptm] = s.[twoptm]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [att], [cmp], [yds], [tds], [ints], [twopta], [twoptm])
+		VALUES (s.playerid, s.gamedate, s.[att], s.[cmp], s.[yds], s.[tds], s.[ints], s.[twopta], s.[twoptm]);
+END
+GO
+
+DROP PROCEDURE SaveRushingStats
+GO
+
+DROP TYPE rushingStatsTvp
+GO
+
+CREATE TYPE rushingStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[att] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL,
+	[twopta] int NULL,
+	[twoptm] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveRushingStats @records rushingStatsTvp READONLY AS
+BEGIN
+	MERGE RushingStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [att] = s.[att], [yds] = s.[yds], [tds] = s.[tds], [lng] = s.[lng], [lngtd] = s.[lngtd], [twopta] = s.[twopta], [twoptm] = s.[twoptm]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [att], [yds], [tds], [lng], [lngtd], [twopta], [twoptm])
+		VALUES (s.playerid, s.gamedate, s.[att], s.[yds], s.[tds], s.[lng], s.[lngtd], s.[twopta], s.[twoptm]);
+END
+GO
+
+DROP PROCEDURE SaveReceivingStats
+GO
+
+DROP TYPE receivingStatsTvp
+GO
+
+CREATE TYPE receivingStatsTvp AS TABLE (
+	playerid varchar(20) NOT NULL,
+	gamedate date NOT NULL,
+	[rec] int NULL,
+	[yds] int NULL,
+	[tds] int NULL,
+	[lng] int NULL,
+	[lngtd] int NULL,
+	[twopta] int NULL,
+	[twoptm] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveReceivingStats @records receivingStatsTvp READONLY AS
+BEGIN
+	MERGE ReceivingStats t
+	USING @records s
+	ON t.playerid = s.playerid AND t.gamedate = s.gamedate
+	WHEN MATCHED THEN UPDATE SET [rec] = s.[rec], [yds] = s.[yds], [tds] = s.[tds], [lng] = s.[lng], [lngtd] = s.[lngtd], [twopta] = s.[twopta], [twoptm] = s.[twoptm]
+	WHEN NOT MATCHED THEN INSERT (playerid, gamedate, [rec], [yds], [tds], [lng], [lngtd], [twopta], [twoptm])
+		VALUES (s.playerid, s.gamedate, s.[rec], s.[yds], s.[tds], s.[lng], s.[lngtd], s.[twopta], s.[twoptm]);
+END
+GO
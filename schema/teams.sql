@@ -0,0 +1,149 @@
+-- Each team's stats for a game. top is the seconds of possession
+
+CREATE TABLE TeamGameStats (
+	teamAbbr varchar(3) NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	totfd int NOT NULL,
+	totyds int NOT NULL,
+	pyds int NOT NULL,
+	ryds int NOT NULL,
+	pen int NOT NULL,
+	penyds int NOT NULL,
+	trnovr int NOT NULL,
+	[top] int NOT NULL,
+	PRIMARY KEY (gamekey, teamAbbr)
+)
+GO
+
+CREATE TYPE TeamGameStatsTvp AS TABLE (
+	teamAbbr varchar(3) NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	totfd int NOT NULL,
+	totyds int NOT NULL,
+	pyds int NOT NULL,
+	ryds int NOT NULL,
+	pen int NOT NULL,
+	penyds int NOT NULL,
+	trnovr int NOT NULL,
+	[top] int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveTeamGameStats @records TeamGameStatsTvp READONLY AS
+BEGIN
+	MERGE TeamGameStats t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.teamAbbr = s.teamAbbr
+	WHEN MATCHED THEN UPDATE SET gamedate = s.gamedate, totfd = s.totfd, totyds = s.totyds, pyds = s.pyds,
+		ryds = s.ryds, pen = s.pen, penyds = s.penyds, trnovr = s.trnovr, [top] = s.[top]
+	WHEN NOT MATCHED THEN INSERT (teamAbbr, gamekey, gamedate, totfd, totyds, pyds, ryds, pen, penyds, trnovr, [top])
+		VALUES (s.teamAbbr, s.gamekey, s.gamedate, s.totfd, s.totyds, s.pyds, s.ryds, s.pen, s.penyds, s.trnovr, s.[top]);
+END
+GO
+
+-- Each drive in a game. postime is the seconds of possession
+CREATE TABLE Drive (
+	gamekey varchar(10) NOT NULL,
+	drivenum int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	startqtr int NOT NULL,
+	starttime varchar(5) NOT NULL,
+	startyrdln varchar(10) NOT NULL,
+	endqtr int NOT NULL,
+	endtime varchar(5) NOT NULL,
+	endyrdln varchar(10) NOT NULL,
+	numplays int NOT NULL,
+	ydsgained int NOT NULL,
+	fds int NOT NULL,
+	penyds int NOT NULL,
+	postime int NOT NULL,
+	redzone bit NOT NULL,
+	result varchar(30) NOT NULL,
+	points int NOT NULL,
+	PRIMARY KEY (gamekey, drivenum)
+)
+GO
+
+CREATE TYPE DriveTvp AS TABLE (
+	gamekey varchar(10) NOT NULL,
+	drivenum int NOT NULL,
+	teamAbbr varchar(3) NOT NULL,
+	startqtr int NOT NULL,
+	starttime varchar(5) NOT NULL,
+	startyrdln varchar(10) NOT NULL,
+	endqtr int NOT NULL,
+	endtime varchar(5) NOT NULL,
+	endyrdln varchar(10) NOT NULL,
+	numplays int NOT NULL,
+	ydsgained int NOT NULL,
+	fds int NOT NULL,
+	penyds int NOT NULL,
+	postime int NOT NULL,
+	redzone bit NOT NULL,
+	result varchar(30) NOT NULL,
+	points int NOT NULL
+)
+GO
+
+CREATE PROCEDURE SaveDrive @records DriveTvp READONLY AS
+BEGIN
+	MERGE Drive t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.drivenum = s.drivenum
+	WHEN MATCHED THEN UPDATE SET teamAbbr = s.teamAbbr, startqtr = s.startqtr, starttime = s.starttime,
+		startyrdln = s.startyrdln, endqtr = s.endqtr, endtime = s.endtime, endyrdln = s.endyrdln,
+		numplays = s.numplays, ydsgained = s.ydsgained, fds = s.fds, penyds = s.penyds, postime = s.postime,
+		redzone = s.redzone, result = s.result, points = s.points
+	WHEN NOT MATCHED THEN INSERT (gamekey, drivenum, teamAbbr, startqtr, starttime, startyrdln, endqtr, endtime,
+		endyrdln, numplays, ydsgained, fds, penyds, postime, redzone, result, points)
+		VALUES (s.gamekey, s.drivenum, s.teamAbbr, s.startqtr, s.starttime, s.startyrdln, s.endqtr, s.endtime,
+		s.endyrdln, s.numplays, s.ydsgained, s.fds, s.penyds, s.postime, s.redzone, s.result, s.points);
+END
+GO
+
+-- Team stats that weren't in the feed are saved as NULL rather than 0. The procedure is dropped first as it
+-- uses the type
+ALTER TABLE TeamGameStats ALTER COLUMN totfd int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN totyds int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN pyds int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN ryds int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN pen int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN penyds int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN trnovr int NULL
+ALTER TABLE TeamGameStats ALTER COLUMN [top] int NULL
+GO
+
+DROP PROCEDURE SaveTeamGameStats
+GO
+
+DROP TYPE TeamGameStatsTvp
+GO
+
+CREATE TYPE TeamGameStatsTvp AS TABLE (
+	teamAbbr varchar(3) NOT NULL,
+	gamekey varchar(10) NOT NULL,
+	gamedate date NOT NULL,
+	totfd int NULL,
+	totyds int NULL,
+	pyds int NULL,
+	ryds int NULL,
+	pen int NULL,
+	penyds int NULL,
+	trnovr int NULL,
+	[top] int NULL
+)
+GO
+
+CREATE PROCEDURE SaveTeamGameStats @records TeamGameStatsTvp READONLY AS
+BEGIN
+	MERGE TeamGameStats t
+	USING @records s
+	ON t.gamekey = s.gamekey AND t.teamAbbr = s.teamAbbr
+	WHEN MATCHED THEN UPDATE SET gamedate = s.gamedate, totfd = s.totfd, totyds = s.totyds, pyds = s.pyds,
+		ryds = s.ryds, pen = s.pen, penyds = s.penyds, trnovr = s.trnovr, [top] = s.[top]
+	WHEN NOT MATCHED THEN INSERT (teamAbbr, gamekey, gamedate, totfd, totyds, pyds, ryds, pen, penyds, trnovr, [top])
+		VALUES (s.teamAbbr, s.gamekey, s.gamedate, s.totfd, s.totyds, s.pyds, s.ryds, s.pen, s.penyds, s.trnovr, s.[top]);
+END
+GO
@@ -0,0 +1,90 @@
+package update
+
+import (
+	"encoding/xml"
+	"../domain"
+	"../repository"
+	"fmt"
+	"net/http"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// The score strip lists a week's games, including the ones that haven't been played
+type scoreStrip struct {
+	Games []scoreStripGame `xml:"gms>g"`
+}
+
+// A game in the score strip. eid is the same key the game's feed uses
+type scoreStripGame struct {
+	Eid string `xml:"eid,attr"`
+	Status string `xml:"q,attr"`
+	HomeAbbr string `xml:"h,attr"`
+	HomeScore string `xml:"hs,attr"`
+	AwayAbbr string `xml:"v,attr"`
+	AwayScore string `xml:"vs,attr"`
+}
+
+// Update the schedule of every regular season week in the season, so games that haven't been played are known
+func updateSchedule(season int) {
+	var schedule []domain.ScheduledGame
+	for week := 1; week <= domain.GetRegularSeasonWeeks(season); week++ {
+		schedule = append(schedule, getWeekSchedule(season, week)...)
+	}
+
+	gameRepository := repository.NewGameSqlRepository()
+	gameRepository.SaveSchedule(schedule)
+}
+
+// Get a week's games from the score strip
+func getWeekSchedule(season int, week int) []domain.ScheduledGame {
+	url := fmt.Sprintf("http://www.nfl.com/ajax/scorestrip?season=%d&seasonType=REG&week=%d", season, week)
+	fmt.Printf("Updating schedule for season %v week %v\n", season, week)
+	// A week that can't be read is skipped rather than stopping the update, and is tried again next update
+	response, err := http.Get(url)
+	if err != nil {
+		fmt.Printf("Skipping schedule for season %v week %v: %v\n", season, week, err)
+		return nil
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		fmt.Printf("Schedule not found for season %v week %v\n", season, week)
+		return nil
+	}
+
+	bytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		fmt.Printf("Skipping schedule for season %v week %v: %v\n", season, week, err)
+		return nil
+	}
+	var strip scoreStrip
+	err = xml.Unmarshal(bytes, &strip)
+	if err != nil {
+		fmt.Printf("Skipping schedule for season %v week %v: %v\n", season, week, err)
+		return nil
+	}
+
+	var schedule []domain.ScheduledGame
+	for _, game := range strip.Games {
+		// The key starts with the game's date, such as 2018090600
+		if len(game.Eid) != 10 {
+			fmt.Println("Skipping scheduled game with an unknown key: " + game.Eid)
+			continue
+		}
+		gameDate, err := time.Parse("20060102", game.Eid[:8])
+		if err != nil {
+			fmt.Println("Skipping scheduled game with an unknown key: " + game.Eid)
+			continue
+		}
+
+		homeScore, _ := strconv.Atoi(game.HomeScore)
+		awayScore, _ := strconv.Atoi(game.AwayScore)
+		schedule = append(schedule, domain.ScheduledGame {
+			Game: domain.NewGame(game.Eid, gameDate, game.HomeAbbr, game.AwayAbbr, homeScore, awayScore),
+			Status: game.Status,
+		})
+	}
+	return schedule
+}
@@ -5,12 +5,12 @@ import (
 	"../domain"
 	"../repository"
 	"../utils"
-	"strings"
 	"time"
 	"fmt"
 	"net/http"
 	"io/ioutil"
 	"strconv"
+	"strings"
 )
 
 var (
@@ -42,6 +42,9 @@ func StartUpdateDataProcess() {
 		// If game data was found, go to the next game on the same date
 		gameNum++
 	}
+
+	// The feed only has games that have started, so the rest of the season comes from the schedule
+	updateSchedule(domain.GetSeason(time.Now()))
 }
 
 // Set the date as today
@@ -140,6 +143,250 @@ func saveGameData(data map[string]interface{}) {
 	awayGameData := getGameDataForTeam(awayMap)
 
 	saveStatsToDb(awayGameData)
+
+	game, ok := getGame(homeMap, awayMap)
+	if ok {
+		statsRepository := repository.NewStatsSqlRepository()
+		statsRepository.SaveGame(game)
+
+		teamRepository := repository.NewTeamSqlRepository()
+		teamRepository.SaveTeamGameStats([]domain.TeamGameStats {
+			getTeamGameStats(homeMap, game),
+			getTeamGameStats(awayMap, game),
+		})
+
+		drivesData := assertToMap(data["drives"])
+		driveRepository := repository.NewDriveSqlRepository()
+		driveRepository.SaveDrives(getDrives(drivesData, game))
+
+		playRepository := repository.NewPlaySqlRepository()
+		playRepository.SavePlays(getPlays(drivesData, game))
+
+		gameRepository := repository.NewGameSqlRepository()
+		gameRepository.SaveLinescores([]domain.TeamLinescore {
+			getLinescore(homeMap, game),
+			getLinescore(awayMap, game),
+		})
+		gameRepository.SaveScoringPlays(getScoringPlays(assertToMap(data["scrsummary"]), game))
+	}
+}
+
+// Get the team's points per quarter from the score field, keyed by quarter with 5 for overtime
+func getLinescore(teamData map[string]interface{}, game domain.Game) domain.TeamLinescore {
+	teamAbbr := getString(teamData, "abbr")
+	quarterPoints := make(map[int]int)
+
+	for quarterKey, value := range assertToMap(teamData["score"]) {
+		quarter, err := strconv.Atoi(quarterKey)
+		points, ok := value.(float64)
+		if err != nil || !ok {
+			continue
+		}
+		quarterPoints[quarter] = int(points)
+	}
+
+	return domain.NewTeamLinescore(game.GameKey, teamAbbr, quarterPoints)
+}
+
+// Get the scoring plays from the scoring summary field, keyed by play id
+func getScoringPlays(scoringData map[string]interface{}, game domain.Game) []domain.ScoringPlay {
+	var scoringPlays []domain.ScoringPlay
+
+	for playKey, value := range scoringData {
+		playId, err := strconv.Atoi(playKey)
+		playData, ok := value.(map[string]interface{})
+		if err != nil || !ok {
+			continue
+		}
+
+		scoringType := getString(playData, "type")
+		description := getString(playData, "desc")
+		scorer, scorerKey := getScorer(assertToMap(playData["players"]), description)
+
+		scoringPlays = append(scoringPlays, domain.ScoringPlay {
+			GameKey: game.GameKey,
+			PlayId: playId,
+			TeamAbbr: getString(playData, "team"),
+			Quarter: getInt(playData, "qtr"),
+			Type: scoringType,
+			Description: description,
+			ScorerKey: scorerKey,
+			Scorer: scorer,
+			Points: domain.GetScoringPlayPoints(scoringType, description),
+		})
+	}
+
+	return scoringPlays
+}
+
+// Get the name and id of the player who scored from a scoring play's players field, keyed by name.
+// The description starts with the scorer, so it's the player named first in it
+func getScorer(playersData map[string]interface{}, description string) (string, string) {
+	scorer := ""
+	scorerKey := ""
+	scorerIndex := -1
+
+	for name, value := range playersData {
+		playerKey, ok := value.(string)
+		index := strings.Index(description, name)
+		if !ok || index < 0 {
+			continue
+		}
+
+		if scorerIndex < 0 || index < scorerIndex {
+			scorer = name
+			scorerKey = playerKey
+			scorerIndex = index
+		}
+	}
+
+	return scorer, scorerKey
+}
+
+// Get the game's drives from the drives field, keyed by drive number
+func getDrives(drivesData map[string]interface{}, game domain.Game) []domain.Drive {
+	var drives []domain.Drive
+
+	for driveKey, value := range drivesData {
+		// The drives field also has the current drive number, which isn't a drive
+		driveNum, err := strconv.Atoi(driveKey)
+		driveData, ok := value.(map[string]interface{})
+		if err != nil || !ok {
+			continue
+		}
+
+		teamAbbr, ok := driveData["posteam"].(string)
+		if !ok {
+			continue
+		}
+
+		startData := assertToMap(driveData["start"])
+		endData := assertToMap(driveData["end"])
+		result, _ := driveData["result"].(string)
+		isRedZone, _ := driveData["redzone"].(bool)
+		possessionTime, _ := driveData["postime"].(string)
+
+		drives = append(drives, domain.Drive {
+			GameKey: game.GameKey,
+			DriveNum: driveNum,
+			TeamAbbr: teamAbbr,
+			StartQuarter: getInt(startData, "qtr"),
+			StartTime: getString(startData, "time"),
+			StartYardLine: getString(startData, "yrdln"),
+			EndQuarter: getInt(endData, "qtr"),
+			EndTime: getString(endData, "time"),
+			EndYardLine: getString(endData, "yrdln"),
+			Plays: getInt(driveData, "numplays"),
+			Yards: getInt(driveData, "ydsgained"),
+			FirstDowns: getInt(driveData, "fds"),
+			PenaltyYards: getInt(driveData, "penyds"),
+			PossessionTime: domain.ParseClockSeconds(possessionTime),
+			IsRedZone: isRedZone,
+			Result: result,
+			Points: domain.GetDrivePoints(result, getPlayDescriptions(assertToMap(driveData["plays"]))),
+		})
+	}
+
+	return drives
+}
+
+// Get every play in the game from the plays field of each drive, keyed by play id
+func getPlays(drivesData map[string]interface{}, game domain.Game) []domain.Play {
+	var plays []domain.Play
+
+	for driveKey, value := range drivesData {
+		driveNum, err := strconv.Atoi(driveKey)
+		if err != nil {
+			continue
+		}
+
+		for playKey, playValue := range assertToMap(assertToMap(value)["plays"]) {
+			playId, err := strconv.Atoi(playKey)
+			playData, ok := playValue.(map[string]interface{})
+			if err != nil || !ok {
+				continue
+			}
+
+			teamAbbr := getString(playData, "posteam")
+			yardLine := getString(playData, "yrdln")
+			fieldPosition, _ := domain.GetFieldPosition(yardLine, teamAbbr)
+
+			plays = append(plays, domain.Play {
+				GameKey: game.GameKey,
+				PlayId: playId,
+				DriveNum: driveNum,
+				TeamAbbr: teamAbbr,
+				Quarter: getInt(playData, "qtr"),
+				Down: getInt(playData, "down"),
+				YardsToGo: getInt(playData, "ydstogo"),
+				Time: getString(playData, "time"),
+				YardLine: yardLine,
+				FieldPosition: fieldPosition,
+				Yards: getInt(playData, "ydsnet"),
+				Description: getString(playData, "desc"),
+				Note: getString(playData, "note"),
+				Players: getPlayPlayers(assertToMap(playData["players"])),
+			})
+		}
+	}
+
+	return plays
+}
+
+// Get the players involved in a play from its players field, keyed by the player's id. Each player
+// has an entry for every stat they recorded on the play
+func getPlayPlayers(playersData map[string]interface{}) []domain.PlayPlayer {
+	var players []domain.PlayPlayer
+
+	for playerKey, value := range playersData {
+		// Team stats such as first downs are under a player key of 0
+		if playerKey == "0" {
+			continue
+		}
+
+		entries, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, entry := range entries {
+			entryData := assertToMap(entry)
+			players = append(players, domain.PlayPlayer {
+				PlayerKey: playerKey,
+				Name: getString(entryData, "playerName"),
+				TeamAbbr: getString(entryData, "clubcode"),
+				StatId: getInt(entryData, "statId"),
+				Yards: getInt(entryData, "yards"),
+			})
+		}
+	}
+
+	return players
+}
+
+// Get the description of each play in a drive's plays field
+func getPlayDescriptions(playsData map[string]interface{}) []string {
+	var descriptions []string
+	for _, value := range playsData {
+		description := getString(assertToMap(value), "desc")
+		if len(description) > 0 {
+			descriptions = append(descriptions, description)
+		}
+	}
+	return descriptions
+}
+
+// Get the team's stats for the game from the team section of its stats
+func getTeamGameStats(teamData map[string]interface{}, game domain.Game) domain.TeamGameStats {
+	teamAbbr, _ := teamData["abbr"].(string)
+	teamStats := assertToMap(assertToMap(teamData["stats"])["team"])
+
+	return domain.TeamGameStats {
+		TeamAbbr: teamAbbr,
+		GameDate: game.GameDate,
+		GameContext: domain.NewGameContext(game, teamAbbr),
+		Stats: domain.NewTeamStats(teamStats),
+	}
 }
 
 func saveStatsToDb(statsMap map[string]domain.PlayerStats) {
@@ -147,6 +394,35 @@ func saveStatsToDb(statsMap map[string]domain.PlayerStats) {
 	statsRepository.SavePlayerStatsBatch(statsMap)
 }
 
+// Get the game's teams and scores from the home and away fields
+func getGame(homeData map[string]interface{}, awayData map[string]interface{}) (domain.Game, bool) {
+	homeAbbr, okHome := homeData["abbr"].(string)
+	awayAbbr, okAway := awayData["abbr"].(string)
+
+	if !okHome || !okAway {
+		return domain.Game{}, false
+	}
+
+	game := domain.NewGame(getGameDateKey(gameDate, gameNum),
+		gameDate,
+		homeAbbr,
+		awayAbbr,
+		getTotalScore(homeData),
+		getTotalScore(awayData))
+	return game, true
+}
+
+// Get the team's total points from the score field, keyed by quarter with "T" for the total
+func getTotalScore(teamData map[string]interface{}) int {
+	score := assertToMap(teamData["score"])
+	total, ok := score["T"].(float64)
+
+	if !ok {
+		return 0
+	}
+	return int(total)
+}
+
 // Get data for the team; home or away fields
 func getGameDataForTeam(teamData map[string]interface{}) map[string]domain.PlayerStats {
 	if !containsKey(teamData, "abbr") || !containsKey(teamData, "stats") {
@@ -161,29 +437,14 @@ func getGameDataForTeam(teamData map[string]interface{}) map[string]domain.Playe
 	}
 
 	teamStats := assertToMap(teamData["stats"])
-
-	passingStatsKey := "passing"
-	rushingStatsKey := "rushing"
-	receivingStatsKey := "receiving"
-
 	playerData := make(map[string]domain.PlayerStats)
-	hasTeamPassingStats := containsKey(teamStats, passingStatsKey)
-	hasTeamRushingStats := containsKey(teamStats, rushingStatsKey)
-	hasTeamReceivingStats := containsKey(teamStats, receivingStatsKey)
-
-	if hasTeamPassingStats {
-		teamPassingStats := assertToMap(teamStats[passingStatsKey])
-		playerData = addTeamStatsToPlayerData(teamPassingStats, playerData, passingStatsKey, teamAbbr)
-	}
 
-	if hasTeamRushingStats {
-		teamRushingStats := assertToMap(teamStats[rushingStatsKey])
-		playerData = addTeamStatsToPlayerData(teamRushingStats, playerData, rushingStatsKey, teamAbbr)
-	}
-
-	if hasTeamReceivingStats {
-		teamReceivingStats := assertToMap(teamStats[receivingStatsKey])
-		playerData = addTeamStatsToPlayerData(teamReceivingStats, playerData, receivingStatsKey, teamAbbr)
+	// Add the stats for each category the team has in the feed
+	for _, category := range domain.GetStatCategories() {
+		if containsKey(teamStats, category.FeedKey) {
+			teamCategoryStats := assertToMap(teamStats[category.FeedKey])
+			playerData = addTeamStatsToPlayerData(teamCategoryStats, playerData, category.FeedKey, teamAbbr)
+		}
 	}
 
 	return playerData
@@ -193,42 +454,28 @@ func addTeamStatsToPlayerData(teamStats map[string]interface{}, playerData map[s
 	statsType string, teamAbbr string) map[string]domain.PlayerStats {
 	for playerKey, value := range teamStats {
 		statsMap := assertToMap(value)
-		statsLabels := domain.GetStatsLabels(statsType)
+		category, ok := domain.GetStatCategory(statsType)
+		if !ok {
+			continue
+		}
 
-		// Check if all the stat values exist in the data
-		if !containsKeys(statsMap, statsLabels) {
+		// Check if any of the stat values exist in the data. Missing values are kept as unknown
+		if !containsAnyKey(statsMap, category.GetFeedLabels()) {
 			continue
 		}
 
-		var player domain.PlayerStats
 		var name string
 
-		name, ok := statsMap["name"].(string)
+		name, ok = statsMap["name"].(string)
 		if !ok {
 			continue
 		}
 
-
-		switch strings.ToLower(statsType) {
-			case "passing":
-				passingStats := domain.NewPassingStats(statsMap)
-				player = getPlayerWithPassingStats(passingStats, playerData, playerKey)
-				break
-			case "rushing":
-				rushingStats := domain.NewRushingStats(statsMap)
-				player = getPlayerWithRushingStats(rushingStats, playerData, playerKey)
-				break
-			case "receiving":
-				receivingStats := domain.NewReceivingStats(statsMap)
-				player = getPlayerWithReceivingStats(receivingStats, playerData, playerKey)
-				break
-			default:
-				continue
-
-		}
+		player := getPlayerWithCategoryStats(category.NewCategoryStats(statsMap), playerData, playerKey, category.FeedKey)
 
 		player.Name = name
 		player.TeamAbbr = teamAbbr
+		player.GameKey = getGameDateKey(gameDate, gameNum)
 		player.GameDate = time.Date(gameDate.Year(),
 			gameDate.Month(),
 			gameDate.Day(),
@@ -247,42 +494,20 @@ func addTeamStatsToPlayerData(teamStats map[string]interface{}, playerData map[s
 
 
 
-// Return a player with their passing stats added to the object
-func getPlayerWithPassingStats(passingStats domain.PassingStats,
-	playerData map[string]domain.PlayerStats, playerKey string) domain.PlayerStats {
-		player := domain.PlayerStats{}
-
-		if containsPlayerKey(playerData, playerKey) {
-			player = playerData[playerKey]
-		}
-
-		player.PassingStats = passingStats
-		return player
-}
-
-// Return a player with their rushing stats added to the object
-func getPlayerWithRushingStats(rushingStats domain.RushingStats,
-	playerData map[string]domain.PlayerStats, playerKey string) domain.PlayerStats {
+// Return a player with their stats for a category added to the object
+func getPlayerWithCategoryStats(categoryStats domain.CategoryStats,
+	playerData map[string]domain.PlayerStats, playerKey string, statsType string) domain.PlayerStats {
 	player := domain.PlayerStats{}
 
 	if containsPlayerKey(playerData, playerKey) {
 		player = playerData[playerKey]
 	}
 
-	player.RushingStats = rushingStats
-	return player
-}
-
-// Return a player with their receiving stats added to the object
-func getPlayerWithReceivingStats(receivingStats domain.ReceivingStats,
-	playerData map[string]domain.PlayerStats, playerKey string) domain.PlayerStats {
-	player := domain.PlayerStats{}
-
-	if containsPlayerKey(playerData, playerKey) {
-		player = playerData[playerKey]
+	if player.StatLine == nil {
+		player.StatLine = make(domain.StatLine)
 	}
 
-	player.ReceivingStats = receivingStats
+	player.StatLine[statsType] = categoryStats
 	return player
 }
 
@@ -299,6 +524,21 @@ func assertToMap(i interface{}) map[string]interface{} {
 	return m
 }
 
+// Get a number field from a map as an int, or 0 if it isn't a number
+func getInt(dict map[string]interface{}, key string) int {
+	value, ok := dict[key].(float64)
+	if !ok {
+		return 0
+	}
+	return int(value)
+}
+
+// Get a string field from a map, or an empty string if it isn't a string
+func getString(dict map[string]interface{}, key string) string {
+	value, _ := dict[key].(string)
+	return value
+}
+
 // Check if a dictionary contains the given key
 func containsKey(dict map[string]interface{}, keyToCheck string) bool {
 	for key := range dict {
@@ -319,24 +559,14 @@ func containsPlayerKey(dict map[string]domain.PlayerStats, keyToCheck string) bo
 	return false
 }
 
-// check if a dictionary contains the given keys
-func containsKeys(dict map[string]interface{}, keysToCheck []string) bool {
-	// Iterate through each key in the given list
+// check if a dictionary contains at least one of the given keys
+func containsAnyKey(dict map[string]interface{}, keysToCheck []string) bool {
 	for _, keyToCheck := range keysToCheck {
-		isFound := false
-		// interate through the map to see if the key to check is contained in it
-		for dictKey := range dict {
-			// if the key is found, set the bool to true
-			if dictKey == keyToCheck {
-				isFound = true
-			}
-		}
-		// If the isFound bool was never set to true, the map doesn't contain one of the keys
-		if !isFound {
-			return false
+		if containsKey(dict, keyToCheck) {
+			return true
 		}
 	}
 
-	// All keys were found in the map
-	return true
+	// None of the keys were found in the map
+	return false
 }